@@ -35,6 +35,9 @@ const (
 
 	// LevelKey is the key used for log levels
 	LevelKey = slog.LevelKey
+
+	// NameKey is the key used for logger names set by [Logger.Named]
+	NameKey = "logger"
 )
 
 // Level is an alias for slog.Level representing log level severity.
@@ -57,6 +60,9 @@ var (
 	// Int64 creates a 64-bit integer-valued attribute
 	Int64 = slog.Int64
 
+	// Uint64 creates a 64-bit unsigned integer-valued attribute
+	Uint64 = slog.Uint64
+
 	// Time creates a time.Time-valued attribute
 	Time = slog.Time
 
@@ -83,6 +83,18 @@ func Uint(key string, val uint) slog.Attr {
 	return slog.Uint64(key, uint64(val))
 }
 
+// UintPtr creates an attribute from a uint pointer.
+// key: The attribute key
+// val: Pointer to uint value. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func UintPtr(key string, val *uint) slog.Attr {
+	if val == nil {
+		return slog.String(key, "nil")
+	}
+
+	return slog.Uint64(key, uint64(*val))
+}
+
 // Uint32 creates an attribute from a uint32 value.
 // key: The attribute key
 // val: The uint32 value
@@ -103,6 +115,18 @@ func Uint32Ptr(key string, val *uint32) slog.Attr {
 	return slog.Uint64(key, uint64(*val))
 }
 
+// Uint64Ptr creates a uint64 attribute from a pointer.
+// key: The attribute key
+// val: Pointer to uint64 value. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func Uint64Ptr(key string, val *uint64) slog.Attr {
+	if val == nil {
+		return slog.String(key, "nil")
+	}
+
+	return slog.Uint64(key, *val)
+}
+
 // Float32 creates an attribute from a float32 value.
 // key: The attribute key
 // val: The float32 value
@@ -196,6 +196,46 @@ func TestUint32Ptr(t *testing.T) {
 	}
 }
 
+func TestUintPtr(t *testing.T) {
+	tests := []struct {
+		name string
+		val  *uint
+		want slog.Attr
+	}{
+		{"nil pointer", nil, slog.String("key", "nil")},
+		{"positive value", uintPtr(42), slog.Uint64("key", 42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UintPtr("key", tt.val)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("UintPtr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUint64Ptr(t *testing.T) {
+	tests := []struct {
+		name string
+		val  *uint64
+		want slog.Attr
+	}{
+		{"nil pointer", nil, slog.String("key", "nil")},
+		{"max value", uint64Ptr(math.MaxUint64), slog.Uint64("key", math.MaxUint64)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Uint64Ptr("key", tt.val)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("Uint64Ptr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFloat32(t *testing.T) {
 	tests := []struct {
 		name string
@@ -320,7 +360,9 @@ func stringPtr(s string) *string    { return &s }
 func intPtr(i int) *int             { return &i }
 func int32Ptr(i int32) *int32       { return &i }
 func int64Ptr(i int64) *int64       { return &i }
+func uintPtr(i uint) *uint          { return &i }
 func uint32Ptr(i uint32) *uint32    { return &i }
+func uint64Ptr(i uint64) *uint64    { return &i }
 func float32Ptr(f float32) *float32 { return &f }
 func float64Ptr(f float64) *float64 { return &f }
 
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const (
+	// defaultMaxBytes is the default number of payload bytes logged by byte constructors
+	defaultMaxBytes = 256
+
+	// defaultDigestOver disables digests by default
+	defaultDigestOver = 0
+)
+
+// bytesOptions contains configuration for byte slice attributes
+type bytesOptions struct {
+	maxBytes   int // Maximum number of payload bytes to encode, 0 means unlimited
+	digestOver int // Log a SHA-256 digest instead of content above this size, 0 disables it
+}
+
+// BytesOption defines a function type for configuring byte slice attributes
+type BytesOption func(*bytesOptions)
+
+// WithMaxBytes sets the maximum number of payload bytes that are encoded.
+// Longer payloads are cut and marked with "... (truncated N bytes)".
+// maxBytes: Maximum number of bytes, 0 means unlimited
+// Returns: Byte attribute option function
+func WithMaxBytes(maxBytes int) BytesOption {
+	return func(o *bytesOptions) {
+		o.maxBytes = maxBytes
+	}
+}
+
+// WithDigestOver logs a SHA-256 digest instead of the content for large payloads
+// size: Payloads longer than size bytes are replaced by their digest, 0 disables digests
+// Returns: Byte attribute option function
+func WithDigestOver(size int) BytesOption {
+	return func(o *bytesOptions) {
+		o.digestOver = size
+	}
+}
+
+// Bytes creates an attribute from a byte slice logged as text.
+// Non-printable bytes and invalid UTF-8 are escaped Go-style.
+// key: The attribute key
+// val: The payload. If nil, the attribute will be logged as "nil"
+// opts: Size limit and digest options
+// Returns: A structured log attribute
+func Bytes(key string, val []byte, opts ...BytesOption) slog.Attr {
+	return bytesAttr(key, val, quoteBytes, opts)
+}
+
+// Hex creates an attribute from a byte slice logged as a hex string
+// key: The attribute key
+// val: The payload. If nil, the attribute will be logged as "nil"
+// opts: Size limit and digest options
+// Returns: A structured log attribute
+func Hex(key string, val []byte, opts ...BytesOption) slog.Attr {
+	return bytesAttr(key, val, hex.EncodeToString, opts)
+}
+
+// Base64 creates an attribute from a byte slice logged as a standard base64 string
+// key: The attribute key
+// val: The payload. If nil, the attribute will be logged as "nil"
+// opts: Size limit and digest options
+// Returns: A structured log attribute
+func Base64(key string, val []byte, opts ...BytesOption) slog.Attr {
+	return bytesAttr(key, val, base64.StdEncoding.EncodeToString, opts)
+}
+
+// bytesAttr applies the options and encodes the (possibly truncated) payload
+func bytesAttr(key string, val []byte, encode func([]byte) string, opts []BytesOption) slog.Attr {
+	if val == nil {
+		return slog.String(key, "nil")
+	}
+
+	config := &bytesOptions{
+		maxBytes:   defaultMaxBytes,
+		digestOver: defaultDigestOver,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.digestOver > 0 && len(val) > config.digestOver {
+		sum := sha256.Sum256(val)
+
+		return slog.String(key, fmt.Sprintf("sha256:%x (%d bytes)", sum, len(val)))
+	}
+
+	if config.maxBytes > 0 && len(val) > config.maxBytes {
+		return slog.String(key, fmt.Sprintf(
+			"%s... (truncated %d bytes)",
+			encode(val[:config.maxBytes]),
+			len(val)-config.maxBytes,
+		))
+	}
+
+	return slog.String(key, encode(val))
+}
+
+// quoteBytes renders bytes as text with non-printable characters escaped
+func quoteBytes(b []byte) string {
+	q := strconv.Quote(string(b))
+
+	return q[1 : len(q)-1]
+}
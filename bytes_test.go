@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		val  []byte
+		opts []BytesOption
+		want slog.Attr
+	}{
+		{"nil slice", nil, nil, slog.String("key", "nil")},
+		{"empty slice", []byte{}, nil, slog.String("key", "")},
+		{"printable text", []byte("hello"), nil, slog.String("key", "hello")},
+		{"binary data", []byte{'a', 0, 0xff}, nil, slog.String("key", `a\x00\xff`)},
+		{"truncated", []byte("hello world"), []BytesOption{WithMaxBytes(5)}, slog.String("key", "hello... (truncated 6 bytes)")},
+		{"unlimited", bytes.Repeat([]byte("a"), 300), []BytesOption{WithMaxBytes(0)}, slog.String("key", string(bytes.Repeat([]byte("a"), 300)))},
+		{
+			"digest",
+			[]byte("hello"),
+			[]BytesOption{WithDigestOver(4)},
+			slog.String("key", "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824 (5 bytes)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Bytes("key", tt.val, tt.opts...)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		name string
+		val  []byte
+		opts []BytesOption
+		want slog.Attr
+	}{
+		{"nil slice", nil, nil, slog.String("key", "nil")},
+		{"payload", []byte{0xde, 0xad, 0xbe, 0xef}, nil, slog.String("key", "deadbeef")},
+		{"truncated", []byte{0xde, 0xad, 0xbe, 0xef}, []BytesOption{WithMaxBytes(2)}, slog.String("key", "dead... (truncated 2 bytes)")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Hex("key", tt.val, tt.opts...)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("Hex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBase64(t *testing.T) {
+	tests := []struct {
+		name string
+		val  []byte
+		opts []BytesOption
+		want slog.Attr
+	}{
+		{"nil slice", nil, nil, slog.String("key", "nil")},
+		{"payload", []byte("hello"), nil, slog.String("key", "aGVsbG8=")},
+		{"truncated", []byte("hello"), []BytesOption{WithMaxBytes(3)}, slog.String("key", "aGVs... (truncated 2 bytes)")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Base64("key", tt.val, tt.opts...)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("Base64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
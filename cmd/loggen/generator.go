@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// tagName is the struct tag consulted by the generator
+	tagName = "log"
+
+	// redactedValue matches the mask used by logging.Object
+	redactedValue = "[REDACTED]"
+
+	// cycleValue matches the value logged by logging.Object for pointers back to an enclosing struct
+	cycleValue = "[cycle]"
+
+	// valueMethod is the generated method behind LogValue; it threads the pointers being
+	// logged through generated types to stop cycles
+	valueMethod = "loggenValue"
+
+	// loggingImport is the import path of the logging package
+	loggingImport = "github.com/sergei-galichev/logging"
+)
+
+// constructor describes how a field type is turned into an attribute
+type constructor struct {
+	fn   string // constructor name in the logging package
+	zero string // comparison against the zero value for omitempty, %s is the field
+}
+
+var (
+	// valueConstructors maps builtin and well-known type names to constructors
+	valueConstructors = map[string]constructor{
+		"bool":          {fn: "Bool", zero: "%s"},
+		"string":        {fn: "String", zero: `%s != ""`},
+		"int":           {fn: "Int", zero: "%s != 0"},
+		"int32":         {fn: "Int32", zero: "%s != 0"},
+		"int64":         {fn: "Int64", zero: "%s != 0"},
+		"uint":          {fn: "Uint", zero: "%s != 0"},
+		"uint32":        {fn: "Uint32", zero: "%s != 0"},
+		"uint64":        {fn: "Uint64", zero: "%s != 0"},
+		"float32":       {fn: "Float32", zero: "%s != 0"},
+		"float64":       {fn: "Float64", zero: "%s != 0"},
+		"time.Time":     {fn: "Time", zero: "!%s.IsZero()"},
+		"time.Duration": {fn: "Duration", zero: "%s != 0"},
+	}
+
+	// lockTypes are the sync and sync/atomic types that vet copylocks forbids copying
+	lockTypes = map[string]bool{
+		"sync.Cond":      true,
+		"sync.Map":       true,
+		"sync.Mutex":     true,
+		"sync.Once":      true,
+		"sync.Pool":      true,
+		"sync.RWMutex":   true,
+		"sync.WaitGroup": true,
+		"atomic.Bool":    true,
+		"atomic.Int32":   true,
+		"atomic.Int64":   true,
+		"atomic.Pointer": true,
+		"atomic.Uint32":  true,
+		"atomic.Uint64":  true,
+		"atomic.Uintptr": true,
+		"atomic.Value":   true,
+	}
+
+	// pointerConstructors maps pointer element type names to *Ptr constructors
+	pointerConstructors = map[string]string{
+		"bool":    "BoolPtr",
+		"string":  "StringPtr",
+		"int":     "IntPtr",
+		"int32":   "Int32Ptr",
+		"int64":   "Int64Ptr",
+		"uint":    "UintPtr",
+		"uint32":  "Uint32Ptr",
+		"uint64":  "Uint64Ptr",
+		"float32": "Float32Ptr",
+		"float64": "Float64Ptr",
+	}
+)
+
+// Generator holds the parsed package and the output buffer
+type Generator struct {
+	buf     bytes.Buffer
+	pkgName string
+	structs map[string]*ast.StructType
+}
+
+// ParsePackage parses a single package from a directory or a list of files
+// args: A directory or a list of Go files
+// Returns: An error if the files cannot be parsed
+func (g *Generator) ParsePackage(args []string) error {
+	files := args
+
+	if len(args) == 1 && isDirectory(args[0]) {
+		matches, err := filepath.Glob(filepath.Join(args[0], "*.go"))
+		if err != nil {
+			return err
+		}
+
+		files = files[:0:0]
+
+		for _, m := range matches {
+			if !strings.HasSuffix(m, "_test.go") && !strings.HasSuffix(m, "_loggen.go") {
+				files = append(files, m)
+			}
+		}
+	}
+
+	g.structs = make(map[string]*ast.StructType)
+	fset := token.NewFileSet()
+
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		if err := g.parseFile(fset, name, src); err != nil {
+			return err
+		}
+	}
+
+	if g.pkgName == "" {
+		return fmt.Errorf("no Go files found in %s", strings.Join(args, " "))
+	}
+
+	return nil
+}
+
+// parseFile collects the struct declarations of a single file
+func (g *Generator) parseFile(fset *token.FileSet, name string, src []byte) error {
+	f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	if g.pkgName == "" {
+		g.pkgName = f.Name.Name
+	} else if g.pkgName != f.Name.Name {
+		return fmt.Errorf("%s: package %s, expected %s", name, f.Name.Name, g.pkgName)
+	}
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				g.structs[ts.Name.Name] = st
+			}
+		}
+	}
+
+	return nil
+}
+
+// Generate produces the formatted source with LogValue methods for the given types
+// types: Names of struct types declared in the parsed package
+// Returns: Formatted Go source or an error
+func (g *Generator) Generate(types []string) ([]byte, error) {
+	g.buf.Reset()
+
+	generated := make(map[string]bool, len(types))
+	for _, name := range types {
+		generated[name] = true
+	}
+
+	for _, name := range types {
+		st, ok := g.structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in package %s", name, g.pkgName)
+		}
+
+		g.generateType(name, st, generated)
+	}
+
+	body := g.buf.String()
+	g.buf.Reset()
+
+	g.printf("// Code generated by \"loggen -type=%s\"; DO NOT EDIT.\n\n", strings.Join(types, ","))
+	g.printf("package %s\n\n", g.pkgName)
+
+	if strings.Contains(body, "logging.") {
+		g.printf("import (\n\t\"log/slog\"\n\n\t%q\n)\n", loggingImport)
+	} else {
+		g.printf("import \"log/slog\"\n")
+	}
+
+	g.buf.WriteString(body)
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// field is a struct field with its parsed tag options
+type field struct {
+	name      string // Go field name
+	key       string // attribute key
+	typ       ast.Expr
+	embedded  bool
+	omitEmpty bool
+	redact    bool
+	inline    bool
+}
+
+// generateType writes the LogValue method for a single struct type and the method
+// building its value, which fields of generated types call with the visited pointers
+func (g *Generator) generateType(name string, st *ast.StructType, generated map[string]bool) {
+	fields := structFields(st)
+
+	// values holding locks must not be copied, so their methods get pointer receivers
+	recv := name
+	if g.hasLock(st, map[*ast.StructType]bool{}) {
+		recv = "*" + name
+	}
+
+	g.printf("\n// LogValue implements slog.LogValuer.\n")
+	g.printf("func (v %s) LogValue() slog.Value {\n", recv)
+
+	if recv != name {
+		g.printf("if v == nil {\nreturn slog.StringValue(\"nil\")\n}\n\n")
+	}
+
+	g.printf("return v.%s(nil)\n}\n", valueMethod)
+
+	g.printf("\n// %s builds the value of LogValue; visited holds the pointers being logged.\n", valueMethod)
+	g.printf("func (v %s) %s(visited map[any]bool) slog.Value {\n", recv, valueMethod)
+	g.printf("attrs := make([]slog.Attr, 0, %d)\n\n", len(fields))
+
+	for _, f := range fields {
+		g.generateField(f, "v."+f.name, generated, map[string]bool{name: true})
+	}
+
+	g.printf("\nreturn slog.GroupValue(attrs...)\n}\n")
+}
+
+// hasLock reports whether values of a struct type hold a lock, directly or through
+// struct and array fields declared in the package; seen guards against recursive types
+func (g *Generator) hasLock(st *ast.StructType, seen map[*ast.StructType]bool) bool {
+	if seen[st] {
+		return false
+	}
+
+	seen[st] = true
+
+	for _, af := range st.Fields.List {
+		typ := af.Type
+		for {
+			if at, ok := typ.(*ast.ArrayType); ok && at.Len != nil {
+				typ = at.Elt
+			} else {
+				break
+			}
+		}
+
+		switch t := typ.(type) {
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		}
+
+		if inner, ok := g.structs[typeString(typ)]; ok && g.hasLock(inner, seen) {
+			return true
+		}
+
+		if lockTypes[typeString(typ)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateField writes the statements that append a single field to attrs
+// expr: Go expression of the field value
+// inlining: Struct types being inlined, which are not inlined again to stop cycles
+func (g *Generator) generateField(f field, expr string, generated, inlining map[string]bool) {
+	typ := typeString(f.typ)
+
+	star, isPtr := f.typ.(*ast.StarExpr)
+
+	if !f.redact && (f.inline || f.embedded) {
+		base := strings.TrimPrefix(typ, "*")
+
+		if g.generateInline(expr, base, isPtr, generated, inlining) {
+			return
+		}
+
+		// like logging.Object, embedded types declared elsewhere (such as sync.Mutex) are skipped
+		if f.embedded && g.structs[base] == nil {
+			return
+		}
+	}
+
+	var stmt string
+
+	switch {
+	case f.redact:
+		stmt = fmt.Sprintf("attrs = append(attrs, logging.String(%q, %q))", f.key, redactedValue)
+	case isPtr && pointerConstructors[typeString(star.X)] != "":
+		stmt = fmt.Sprintf("attrs = append(attrs, logging.%s(%q, %s))", pointerConstructors[typeString(star.X)], f.key, expr)
+	case isPtr && generated[typeString(star.X)]:
+		stmt = pointerStmt(f, expr)
+	case generated[typ]:
+		stmt = fmt.Sprintf("attrs = append(attrs, slog.Attr{Key: %q, Value: %s.%s(visited)})", f.key, expr, valueMethod)
+	case valueConstructors[typ].fn != "":
+		stmt = fmt.Sprintf("attrs = append(attrs, logging.%s(%q, %s))", valueConstructors[typ].fn, f.key, expr)
+	default:
+		stmt = fmt.Sprintf("attrs = append(attrs, logging.Any(%q, %s))", f.key, expr)
+	}
+
+	if cond := zeroCheck(f, expr); f.omitEmpty && cond != "" {
+		g.printf("if %s {\n%s\n}\n", cond, stmt)
+
+		return
+	}
+
+	g.printf("%s\n", stmt)
+}
+
+// generateInline writes the statements that append the fields of an embedded or inline
+// struct to attrs: through its LogValue method when it is generated, or field by field
+// when it is declared in the package. Types declared elsewhere are not inlined.
+// Returns: Whether the field was inlined
+func (g *Generator) generateInline(expr, typ string, isPtr bool, generated, inlining map[string]bool) bool {
+	st, declared := g.structs[typ]
+	if !generated[typ] && (!declared || inlining[typ]) {
+		return false
+	}
+
+	switch {
+	case generated[typ] && isPtr:
+		g.printf("if %[1]s != nil && !visited[%[1]s] {\n%[2]s\n}\n", expr, visit(expr,
+			fmt.Sprintf("attrs = append(attrs, %s.%s(visited).Group()...)", expr, valueMethod)))
+	case generated[typ]:
+		g.printf("attrs = append(attrs, %s.%s(visited).Group()...)\n", expr, valueMethod)
+	default:
+		if isPtr {
+			g.printf("if %s != nil {\n", expr)
+		}
+
+		inlining[typ] = true
+
+		for _, f := range structFields(st) {
+			g.generateField(f, expr+"."+f.name, generated, inlining)
+		}
+
+		delete(inlining, typ)
+
+		if isPtr {
+			g.printf("}\n")
+		}
+	}
+
+	return true
+}
+
+// pointerStmt returns the statements appending a pointer to a generated type, logged as
+// "nil" when it is nil (unless omitted by omitempty) and "[cycle]" when it refers back
+// to a value being logged
+func pointerStmt(f field, expr string) string {
+	cycle := fmt.Sprintf("attrs = append(attrs, logging.String(%q, %q))", f.key, cycleValue)
+	value := visit(expr, fmt.Sprintf("attrs = append(attrs, slog.Attr{Key: %q, Value: %s.%s(visited)})", f.key, expr, valueMethod))
+
+	if f.omitEmpty {
+		return fmt.Sprintf("if visited[%s] {\n%s\n} else {\n%s\n}", expr, cycle, value)
+	}
+
+	return fmt.Sprintf(
+		"switch {\ncase %[1]s == nil:\nattrs = append(attrs, logging.String(%[2]q, \"nil\"))\ncase visited[%[1]s]:\n%[3]s\ndefault:\n%[4]s\n}",
+		expr, f.key, cycle, value,
+	)
+}
+
+// visit returns the statements running stmt with the pointer expr marked as visited
+func visit(expr, stmt string) string {
+	return fmt.Sprintf(
+		"if visited == nil {\nvisited = make(map[any]bool)\n}\n\nvisited[%[1]s] = true\n%[2]s\ndelete(visited, %[1]s)",
+		expr, stmt,
+	)
+}
+
+// zeroCheck returns a condition that is true when the field holds a non-zero value.
+// An empty string is returned for types whose zero value cannot be detected syntactically.
+func zeroCheck(f field, expr string) string {
+	switch t := f.typ.(type) {
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return expr + " != nil"
+	case *ast.MapType:
+		return "len(" + expr + ") != 0"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "len(" + expr + ") != 0"
+		}
+
+		return ""
+	}
+
+	typ := typeString(f.typ)
+
+	if typ == "error" || typ == "any" {
+		return expr + " != nil"
+	}
+
+	if c, ok := valueConstructors[typ]; ok {
+		return fmt.Sprintf(c.zero, expr)
+	}
+
+	return ""
+}
+
+// structFields returns the loggable fields of a struct in declaration order
+func structFields(st *ast.StructType) []field {
+	fields := make([]field, 0, len(st.Fields.List))
+
+	for _, af := range st.Fields.List {
+		var tag reflect.StructTag
+		if af.Tag != nil {
+			if s, err := strconv.Unquote(af.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+
+		value := tag.Get(tagName)
+		if value == "-" {
+			continue
+		}
+
+		key, opts, _ := strings.Cut(value, ",")
+
+		names := af.Names
+		embedded := len(names) == 0
+
+		if embedded {
+			names = []*ast.Ident{ast.NewIdent(embeddedName(af.Type))}
+		}
+
+		for _, n := range names {
+			if !n.IsExported() && !embedded {
+				continue
+			}
+
+			f := field{
+				name:     n.Name,
+				key:      key,
+				typ:      af.Type,
+				embedded: embedded && key == "",
+			}
+
+			if f.key == "" {
+				f.key = n.Name
+			}
+
+			for _, opt := range strings.Split(opts, ",") {
+				switch strings.TrimSpace(opt) {
+				case "omitempty":
+					f.omitEmpty = true
+				case "redact":
+					f.redact = true
+				case "inline":
+					f.inline = true
+				}
+			}
+
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// embeddedName returns the implicit field name of an embedded type
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+
+	return ""
+}
+
+// typeString renders a type expression as Go source
+func typeString(expr ast.Expr) string {
+	var buf bytes.Buffer
+
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+
+	return buf.String()
+}
+
+// printf appends formatted text to the output buffer
+func (g *Generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
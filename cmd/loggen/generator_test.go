@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		types []string
+	}{
+		{"user", "user.go", []string{"Base", "Address", "User"}},
+		{"user_only", "user.go", []string{"User"}},
+		{"empty", "user.go", []string{"Empty"}},
+		{"node", "node.go", []string{"Node", "Tree"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{}
+
+			if err := g.ParsePackage([]string{filepath.Join("testdata", tt.input)}); err != nil {
+				t.Fatalf("ParsePackage() error = %v", err)
+			}
+
+			got, err := g.Generate(tt.types)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("Generate() mismatch with %s:\n%s", golden, got)
+			}
+
+			checkCompiles(t, filepath.Join("testdata", tt.input), got)
+		})
+	}
+}
+
+// checkCompiles vets the generated code along with its input in a package under testdata,
+// where it can import the logging package of this module
+func checkCompiles(t *testing.T, input string, generated []byte) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir, err := os.MkdirTemp("testdata", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	src, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{filepath.Base(input): src, "loggen.go": generated} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out, err := exec.Command(goTool, "vet", "./"+filepath.ToSlash(dir)).CombinedOutput(); err != nil {
+		t.Errorf("generated code does not compile: %v\n%s", err, out)
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	g := &Generator{}
+
+	if err := g.ParsePackage([]string{filepath.Join("testdata", "user.go")}); err != nil {
+		t.Fatalf("ParsePackage() error = %v", err)
+	}
+
+	_, err := g.Generate([]string{"Missing"})
+	if err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Generate() error = %v, want error mentioning Missing", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Loggen generates LogValue methods for struct types, a reflection-free
+// alternative to logging.Object for hot paths.
+//
+// The generated methods use the typed attribute constructors of the logging
+// package (including the *Ptr helpers for pointer fields) and honor the same
+// `log` struct tag conventions as logging.Object:
+//
+//	Name    string `log:"name"`       // rename the field
+//	Pass    string `log:"-"`          // skip the field
+//	Note    string `log:",omitempty"` // skip the field when it holds a zero value
+//	Token   string `log:",redact"`    // log "[REDACTED]" instead of the value
+//	Address Addr   `log:",inline"`    // merge nested fields into the parent group
+//
+// As with logging.Object, embedded types from other packages are skipped and pointers
+// back to a value being logged are logged as "[cycle]".
+//
+// Typical usage is a go:generate directive next to the type declarations:
+//
+//	//go:generate go run github.com/sergei-galichev/logging/cmd/loggen -type=User,Address
+//
+// Flags:
+//
+//	-type    comma-separated list of struct type names; required
+//	-output  output file name; default <dir>/<first type>_loggen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output    = flag.String("output", "", "output file name; default <dir>/<type>_loggen.go")
+)
+
+// usage prints the command line help
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of loggen:\n")
+	fmt.Fprintf(os.Stderr, "\tloggen -type T[,T...] [directory]\n")
+	fmt.Fprintf(os.Stderr, "\tloggen -type T[,T...] files... # must be a single package\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("loggen: ")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	types := strings.Split(*typeNames, ",")
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	dir := args[0]
+	if len(args) > 1 || !isDirectory(dir) {
+		dir = filepath.Dir(args[0])
+	}
+
+	g := &Generator{}
+
+	if err := g.ParsePackage(args); err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := g.Generate(types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_loggen.go")
+	}
+
+	if err := os.WriteFile(outputName, src, 0o644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+}
+
+// isDirectory reports whether the named file is a directory
+func isDirectory(name string) bool {
+	info, err := os.Stat(name)
+	if err != nil {
+		return false
+	}
+
+	return info.IsDir()
+}
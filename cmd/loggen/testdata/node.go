@@ -0,0 +1,16 @@
+package testdata
+
+import "sync"
+
+type Node struct {
+	sync.Mutex
+	*Node
+	Name   string `log:"name"`
+	Parent *Node  `log:"parent"`
+	Next   *Node  `log:"next,omitempty"`
+	Root   Tree   `log:"root"`
+}
+
+type Tree struct {
+	Top *Node `log:"top"`
+}
@@ -0,0 +1,40 @@
+package testdata
+
+import "time"
+
+type Base struct {
+	ID int64 `log:"id"`
+}
+
+type Address struct {
+	City string `log:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+type Meta struct {
+	Version string `log:"version"`
+}
+
+type Empty struct{}
+
+type User struct {
+	Base
+	*Meta
+	Name     string         `log:"name"`
+	Password string         `log:"password,redact"`
+	Internal string         `log:"-"`
+	Nickname *string        `log:"nickname"`
+	Age      *int           `log:"age,omitempty"`
+	Active   bool           `log:"active"`
+	Score    float32        `log:"score"`
+	Quota    uint64         `log:"quota"`
+	Count    *uint64        `log:"count"`
+	Limit    *uint          `log:"limit,omitempty"`
+	Address  Address        `log:",inline"`
+	Manager  *User          `log:"manager,omitempty"`
+	Created  time.Time      `log:"created,omitempty"`
+	Timeout  time.Duration  `log:"timeout"`
+	Tags     []string       `log:"tags,omitempty"`
+	Extra    map[string]int `log:"extra"`
+	hidden   string
+}
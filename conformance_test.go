@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/slogtest"
+)
+
+// TestSlogConformance runs the testing/slogtest suite against every handler and
+// format of the package. Handlers added to the package belong in this table.
+func TestSlogConformance(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w io.Writer) slog.Handler
+		parse   func(line []byte) (map[string]any, error)
+	}{
+		{
+			"text logger",
+			func(w io.Writer) slog.Handler { return NewLogger(WithWriter(w)).Handler() },
+			parseTextLine,
+		},
+		{
+			"json logger",
+			func(w io.Writer) slog.Handler { return NewLogger(WithWriter(w), WithJSONFormat(true)).Handler() },
+			parseJSONLine,
+		},
+		{
+			"json logger with source, redaction and scrubbing",
+			func(w io.Writer) slog.Handler {
+				return NewLogger(
+					WithWriter(w),
+					WithJSONFormat(true),
+					WithShortSource(true),
+					WithRedactAction(RedactActionHash),
+					WithScrub(ScrubAll),
+				).Handler()
+			},
+			parseJSONLine,
+		},
+		{
+			"context handler",
+			func(w io.Writer) slog.Handler { return NewContextHandler(slog.NewJSONHandler(w, nil)) },
+			parseJSONLine,
+		},
+		{
+			"sampling handler",
+			func(w io.Writer) slog.Handler { return NewSamplingHandler(slog.NewJSONHandler(w, nil)) },
+			parseJSONLine,
+		},
+		{
+			"dedup handler",
+			func(w io.Writer) slog.Handler { return NewDedupHandler(slog.NewJSONHandler(w, nil)) },
+			parseJSONLine,
+		},
+		{
+			"rate limit handler",
+			func(w io.Writer) slog.Handler {
+				return NewRateLimitHandler(slog.NewJSONHandler(w, nil), WithRateLimitKey("tenant_id"))
+			},
+			parseJSONLine,
+		},
+		{
+			"flight recorder handler",
+			func(w io.Writer) slog.Handler { return NewFlightRecorderHandler(slog.NewJSONHandler(w, nil)) },
+			parseJSONLine,
+		},
+		{
+			"routing handler",
+			func(w io.Writer) slog.Handler {
+				return NewRoutingHandler(
+					WithRoute(RouteAttr("audit", true), slog.NewTextHandler(io.Discard, nil)),
+					WithDefaultRoute(slog.NewJSONHandler(w, nil)),
+				)
+			},
+			parseJSONLine,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := slogtest.TestHandler(tt.handler(&buf), func() []map[string]any {
+				return parseLines(t, buf.Bytes(), tt.parse)
+			})
+			if err != nil {
+				t.Errorf("slogtest: %v\noutput:\n%s", err, buf.String())
+			}
+		})
+	}
+}
+
+// parseLines parses one record per non-empty line
+func parseLines(t *testing.T, out []byte, parse func(line []byte) (map[string]any, error)) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range bytes.Split(out, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+
+		m, err := parse(line)
+		if err != nil {
+			t.Fatalf("parse %q: %v", line, err)
+		}
+
+		records = append(records, m)
+	}
+
+	return records
+}
+
+// parseJSONLine parses a record written by a JSON handler
+func parseJSONLine(line []byte) (map[string]any, error) {
+	var m map[string]any
+	err := json.Unmarshal(line, &m)
+
+	return m, err
+}
+
+// parseTextLine parses a record written by a text handler, nesting dotted keys into maps
+func parseTextLine(line []byte) (map[string]any, error) {
+	m := make(map[string]any)
+	s := string(line)
+
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("missing '=' in %q", s)
+		}
+
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var val string
+
+		if strings.HasPrefix(s, `"`) {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, err
+			}
+
+			val, _ = strconv.Unquote(quoted)
+			s = s[len(quoted):]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+
+			val, s = s[:end], s[end:]
+		}
+
+		s = strings.TrimPrefix(s, " ")
+
+		parts := strings.Split(key, ".")
+		group := m
+
+		for _, part := range parts[:len(parts)-1] {
+			sub, ok := group[part].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				group[part] = sub
+			}
+
+			group = sub
+		}
+
+		group[parts[len(parts)-1]] = val
+	}
+
+	return m, nil
+}
@@ -2,6 +2,7 @@ package logging
 
 import (
 	"context"
+	"log/slog"
 )
 
 // loggerKey is a private type used as unique context key to store logger instances.
@@ -52,3 +53,71 @@ func LoggerFromContext(ctx context.Context) *Logger {
 
 	return &Logger{DefaultLogger()}
 }
+
+// attrsKey is a private type used as unique context key to store attributes
+// added by [ContextWithAttrs].
+type attrsKey struct{}
+
+// ContextWithAttrs returns a derived context carrying the given attributes.
+// Loggers created by [NewLogger] add them to every record logged with this context
+// (InfoContext, ErrorContext, FatalContext and so on).
+// Attributes already stored in ctx are kept; an attribute with the same key
+// replaces the earlier value while keeping its original position.
+//
+// Parameters:
+//   - ctx: parent context
+//   - attrs: attributes to add to every record
+//
+// Returns:
+//   - new context.Context containing the merged attributes
+//
+// Example:
+//
+//	ctx = ContextWithAttrs(ctx, String("request_id", id), Int("user_id", uid))
+//	logger.InfoContext(ctx, "order created") // includes request_id and user_id
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	existing := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, len(existing), len(existing)+len(attrs))
+	copy(merged, existing)
+
+	for _, a := range attrs {
+		merged = mergeAttr(merged, a)
+	}
+
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// AttrsFromContext returns the attributes stored by [ContextWithAttrs].
+// The returned slice must not be modified.
+//
+// Parameters:
+//   - ctx: context containing the attributes
+//
+// Returns:
+//   - stored attributes or nil if there are none
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	return attrs
+}
+
+// mergeAttr replaces the attribute with the same key or appends a new one
+func mergeAttr(attrs []slog.Attr, a slog.Attr) []slog.Attr {
+	for i := range attrs {
+		if attrs[i].Key == a.Key {
+			attrs[i] = a
+
+			return attrs
+		}
+	}
+
+	return append(attrs, a)
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sergei-galichev/logging/internal/topattrs"
+)
+
+// ContextHandler is a [slog.Handler] middleware that adds the attributes stored
+// by [ContextWithAttrs] to every record handled with that context.
+// Context attributes are placed at the top level, after the attributes added
+// via With and before the record's own attributes, even when the logger has
+// groups; when a record or the logger (via With) already has a top-level
+// attribute with the same key, the context value is skipped.
+//
+// With groups, the context attributes are added by rebuilding the handlers
+// below it with WithAttrs and WithGroup. The rebuilt handler is reused for the
+// following records with the same context attributes, but records of concurrent
+// requests logged through the same grouped logger may each pay for it.
+type ContextHandler struct {
+	next  slog.Handler
+	keys  map[string]struct{} // top-level keys added via WithAttrs
+	chain topattrs.Chain      // groups and attributes added since the first group
+}
+
+// contextAttrsKey identifies the attributes stored in a context
+type contextAttrsKey struct {
+	first *slog.Attr
+	n     int
+}
+
+// NewContextHandler wraps a handler with context attribute propagation
+// next: Handler receiving the enriched records
+// Returns: Context-aware handler
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled implements [slog.Handler]
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := AttrsFromContext(ctx)
+	if len(attrs) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if h.chain.Grouped() {
+		// context attributes are never modified, so the slice identifies them
+		next := h.chain.CachedHandler(contextAttrsKey{first: &attrs[0], n: len(attrs)}, func() []slog.Attr {
+			// record attributes are inside the groups and cannot clash with context ones
+			top := make([]slog.Attr, 0, len(attrs))
+
+			for _, a := range attrs {
+				if _, ok := h.keys[a.Key]; !ok {
+					top = append(top, a)
+				}
+			}
+
+			return top
+		})
+
+		return next.Handle(ctx, r)
+	}
+
+	own := make(map[string]struct{}, r.NumAttrs()+len(h.keys))
+	for key := range h.keys {
+		own[key] = struct{}{}
+	}
+
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+
+	r.Attrs(func(a slog.Attr) bool {
+		own[a.Key] = struct{}{}
+		recordAttrs = append(recordAttrs, a)
+
+		return true
+	})
+
+	merged := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	for _, a := range attrs {
+		if _, ok := own[a.Key]; !ok {
+			merged.AddAttrs(a)
+		}
+	}
+
+	merged.AddAttrs(recordAttrs...)
+
+	return h.next.Handle(ctx, merged)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := &ContextHandler{
+		next:  h.next.WithAttrs(attrs),
+		keys:  h.keys,
+		chain: h.chain.WithAttrs(attrs),
+	}
+
+	if !h.chain.Grouped() {
+		h2.keys = make(map[string]struct{}, len(h.keys)+len(attrs))
+		for key := range h.keys {
+			h2.keys[key] = struct{}{}
+		}
+
+		for _, a := range attrs {
+			h2.keys[a.Key] = struct{}{}
+		}
+	}
+
+	return h2
+}
+
+// WithGroup implements [slog.Handler]
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &ContextHandler{
+		next:  h.next.WithGroup(name),
+		keys:  h.keys,
+		chain: h.chain.WithGroup(h.next, name),
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestContextWithAttrs(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), String("request_id", "r1"), Int("user_id", 1))
+	child := ContextWithAttrs(ctx, Int("user_id", 2), String("tenant", "acme"))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"parent", ctx, "request_id=r1 user_id=1"},
+		{"child overrides in place", child, "request_id=r1 user_id=2 tenant=acme"},
+		{"empty", context.Background(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderText(t, AttrsFromContext(tt.ctx)...)
+			if got != tt.want {
+				t.Errorf("AttrsFromContext() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), String("request_id", "r1"), String("user", "ctx"))
+
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{
+			"context attrs come first",
+			func(l *slog.Logger) { l.InfoContext(ctx, "msg", "k", "v") },
+			"msg=msg request_id=r1 user=ctx k=v",
+		},
+		{
+			"record attr wins",
+			func(l *slog.Logger) { l.ErrorContext(ctx, "msg", "user", "record") },
+			"msg=msg request_id=r1 user=record",
+		},
+		{
+			"no context attrs",
+			func(l *slog.Logger) { l.Info("msg") },
+			"msg=msg",
+		},
+		{
+			"with attrs",
+			func(l *slog.Logger) { l.With("svc", "api").InfoContext(ctx, "msg") },
+			"msg=msg svc=api request_id=r1 user=ctx",
+		},
+		{
+			"logger attr wins",
+			func(l *slog.Logger) { l.With("request_id", "logger").InfoContext(ctx, "msg") },
+			"msg=msg request_id=logger user=ctx",
+		},
+		{
+			"group keeps context attrs at the top level",
+			func(l *slog.Logger) { l.WithGroup("db").InfoContext(ctx, "msg", "k", "v") },
+			"msg=msg request_id=r1 user=ctx db.k=v",
+		},
+		{
+			"grouped record attr does not hide context attr",
+			func(l *slog.Logger) { l.WithGroup("db").InfoContext(ctx, "msg", "user", "record") },
+			"msg=msg request_id=r1 user=ctx db.user=record",
+		},
+		{
+			"logger attr before group wins",
+			func(l *slog.Logger) { l.With("user", "logger").WithGroup("db").InfoContext(ctx, "msg") },
+			"msg=msg user=logger request_id=r1",
+		},
+		{
+			"attrs and nested groups after the first group",
+			func(l *slog.Logger) {
+				l.With("svc", "api").WithGroup("db").With("user", "logger").WithGroup("q").InfoContext(ctx, "msg", "n", 1)
+			},
+			"msg=msg svc=api request_id=r1 user=ctx db.user=logger db.q.n=1",
+		},
+		{
+			"grouped logger alternating contexts",
+			func(l *slog.Logger) {
+				db := l.WithGroup("db")
+				other := ContextWithAttrs(context.Background(), String("request_id", "r2"))
+
+				db.InfoContext(ctx, "msg", "n", 1)
+				db.InfoContext(other, "msg", "n", 2)
+				db.InfoContext(ctx, "msg", "n", 3)
+			},
+			"msg=msg request_id=r1 user=ctx db.n=1\nmsg=msg request_id=r2 db.n=2\nmsg=msg request_id=r1 user=ctx db.n=3",
+		},
+		{
+			"group without context attrs",
+			func(l *slog.Logger) { l.WithGroup("db").Info("msg", "k", "v") },
+			"msg=msg db.k=v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(slog.New(NewContextHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: dropTimeAndLevel,
+			}))))
+
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkContextHandler(b *testing.B) {
+	logger := slog.New(NewContextHandler(slog.NewTextHandler(io.Discard, nil)))
+	ctx := ContextWithAttrs(context.Background(), String("request_id", "r1"), String("tenant", "acme"))
+
+	for _, bm := range []struct {
+		name   string
+		logger *slog.Logger
+	}{
+		{"flat", logger},
+		{"grouped", logger.WithGroup("db")},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				bm.logger.InfoContext(ctx, "query", "rows", 1)
+			}
+		})
+	}
+}
+
+// dropTimeAndLevel removes non-deterministic built-in attributes from test output
+func dropTimeAndLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+		return slog.Attr{}
+	}
+
+	return a
+}
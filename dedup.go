@@ -0,0 +1,277 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Default deduplication configuration constants
+	defaultDedupWindow = 10 * time.Second
+
+	// DedupRepeatedKey is the attribute key of the number of collapsed duplicates
+	DedupRepeatedKey = "repeated"
+)
+
+// DedupOptions contains configuration for the deduplication handler
+type DedupOptions struct {
+	Window time.Duration // Maximum duration of a run of collapsed duplicates
+}
+
+// DedupOption defines a function type for configuring DedupOptions
+type DedupOption func(*DedupOptions)
+
+// newDedupOptions applies the options on top of the default configuration
+func newDedupOptions(opts ...DedupOption) *DedupOptions {
+	config := &DedupOptions{
+		Window: defaultDedupWindow,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// dedupRun is the run of identical records currently collapsed
+type dedupRun struct {
+	key      string          // fingerprint of the records of the run
+	start    time.Time       // when the first record of the run was logged
+	repeated int             // number of suppressed duplicates
+	last     slog.Record     // last suppressed duplicate
+	ctx      context.Context // context of the last duplicate, without cancellation
+	next     slog.Handler    // handler that logged the run
+	gen      uint64          // run counter invalidating stale timers
+	timer    *time.Timer
+}
+
+// dedupState holds the run shared by a DedupHandler and the handlers derived from it
+type dedupState struct {
+	opts *DedupOptions
+
+	mu  sync.Mutex
+	run dedupRun
+}
+
+// DedupHandler is a [slog.Handler] middleware that collapses identical consecutive
+// records, such as the same error logged by a retry loop. Records are identical when
+// they have the same level, message, attributes (including those added by With and
+// by [ContextWithAttrs]) and groups.
+//
+// The first record of a run is logged immediately and its duplicates are suppressed.
+// When the run ends, because a different record is logged or [DedupOptions.Window]
+// elapsed since its first record, the last duplicate is logged with the
+// [DedupRepeatedKey] attribute holding the number of suppressed duplicates.
+//
+// Records above Error, such as Fatal ones, and records carrying [slog.LogValuer]
+// attributes, which are only resolved by the next handler, are never collapsed.
+type DedupHandler struct {
+	next  slog.Handler
+	key   string // fingerprint of the attributes and groups added to the handler
+	lazy  bool   // whether the added attributes include LogValuers
+	state *dedupState
+}
+
+// NewDedupHandler wraps a handler with deduplication of consecutive records
+// next: Handler receiving the records
+// opts: Variadic list of configuration options
+// Returns: Deduplicating handler
+func NewDedupHandler(next slog.Handler, opts ...DedupOption) *DedupHandler {
+	return &DedupHandler{
+		next:  next,
+		state: &dedupState{opts: newDedupOptions(opts...)},
+	}
+}
+
+// DedupMiddleware returns a middleware collapsing duplicates, for use with [WithMiddleware]
+// opts: Variadic list of configuration options
+// Returns: Deduplication middleware
+func DedupMiddleware(opts ...DedupOption) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewDedupHandler(next, opts...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	key, ok := h.fingerprint(ctx, r)
+	if !ok || r.Level > LevelError {
+		h.Flush()
+
+		return h.next.Handle(ctx, r)
+	}
+
+	now := time.Now()
+	s := h.state
+
+	s.mu.Lock()
+
+	if s.run.key == key && now.Sub(s.run.start) < s.opts.Window {
+		s.run.repeated++
+		s.run.last = r.Clone()
+		s.run.ctx = context.WithoutCancel(ctx)
+
+		if s.run.repeated == 1 {
+			gen := s.run.gen
+			s.run.timer = time.AfterFunc(s.run.start.Add(s.opts.Window).Sub(now), func() { s.expire(gen) })
+		}
+
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	flush := s.end()
+	s.run = dedupRun{key: key, start: now, next: h.next, gen: s.run.gen + 1}
+
+	s.mu.Unlock()
+
+	flush()
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+
+	b.WriteString(h.key)
+	ok := writeAttrs(&b, attrs)
+
+	return &DedupHandler{next: h.next.WithAttrs(attrs), key: b.String(), lazy: h.lazy || !ok, state: h.state}
+}
+
+// WithGroup implements [slog.Handler]
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &DedupHandler{next: h.next.WithGroup(name), key: h.key + name + ".\x00", lazy: h.lazy, state: h.state}
+}
+
+// Flush logs the pending duplicates of the current run, for example before exiting
+func (h *DedupHandler) Flush() {
+	h.state.mu.Lock()
+	flush := h.state.end()
+	h.state.run = dedupRun{gen: h.state.run.gen + 1}
+	h.state.mu.Unlock()
+
+	flush()
+}
+
+// end stops the current run and returns a function logging its duplicates.
+// It must be called with the mutex held, the returned function without.
+func (s *dedupState) end() func() {
+	run := s.run
+	if run.timer != nil {
+		run.timer.Stop()
+	}
+
+	if run.repeated == 0 {
+		return func() {}
+	}
+
+	return func() {
+		run.last.AddAttrs(Int(DedupRepeatedKey, run.repeated))
+		_ = run.next.Handle(run.ctx, run.last)
+	}
+}
+
+// expire ends the run of the given generation when its window closes
+func (s *dedupState) expire(gen uint64) {
+	s.mu.Lock()
+
+	if s.run.gen != gen {
+		s.mu.Unlock()
+
+		return
+	}
+
+	flush := s.end()
+	s.run = dedupRun{gen: gen + 1}
+
+	s.mu.Unlock()
+
+	flush()
+}
+
+// fingerprint identifies records that are duplicates of each other.
+// It reports false for records carrying LogValuers, which are not resolved here.
+func (h *DedupHandler) fingerprint(ctx context.Context, r slog.Record) (string, bool) {
+	if h.lazy {
+		return "", false
+	}
+
+	var b strings.Builder
+
+	b.WriteString(r.Level.String())
+	b.WriteByte(0)
+	b.WriteString(r.Message)
+	b.WriteByte(0)
+	b.WriteString(h.key)
+
+	ok := writeAttrs(&b, AttrsFromContext(ctx))
+
+	r.Attrs(func(a slog.Attr) bool {
+		ok = ok && writeAttr(&b, a)
+
+		return ok
+	})
+
+	return b.String(), ok
+}
+
+// writeAttrs appends attributes to a fingerprint, reporting false if one is a LogValuer
+func writeAttrs(b *strings.Builder, attrs []slog.Attr) bool {
+	for _, a := range attrs {
+		if !writeAttr(b, a) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeAttr appends an attribute to a fingerprint without resolving it,
+// reporting false if it is or contains a LogValuer
+func writeAttr(b *strings.Builder, a slog.Attr) bool {
+	switch a.Value.Kind() {
+	case slog.KindLogValuer:
+		return false
+	case slog.KindGroup:
+		b.WriteString(a.Key)
+		b.WriteString(".\x00")
+
+		if !writeAttrs(b, a.Value.Group()) {
+			return false
+		}
+
+		b.WriteString("\x00.")
+
+		return true
+	}
+
+	b.WriteString(a.String())
+	b.WriteByte(0)
+
+	return true
+}
+
+// WithDedupWindow sets the maximum duration of a run of collapsed duplicates
+// window: Duration after the first record of a run at which the run ends
+// Returns: Deduplication option function
+func WithDedupWindow(window time.Duration) DedupOption {
+	return func(o *DedupOptions) {
+		o.Window = window
+	}
+}
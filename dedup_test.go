@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDedupHandler(t *testing.T) {
+	ctx1 := ContextWithAttrs(context.Background(), String("request_id", "r1"))
+	ctx2 := ContextWithAttrs(context.Background(), String("request_id", "r2"))
+
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want []string
+	}{
+		{
+			"run ended by another record",
+			func(l *slog.Logger) {
+				for i := 0; i < 3; i++ {
+					l.Error("retry failed", "error", "timeout")
+				}
+
+				l.Info("gave up")
+			},
+			[]string{
+				"msg=\"retry failed\" error=timeout",
+				"msg=\"retry failed\" error=timeout repeated=2",
+				"msg=\"gave up\"",
+			},
+		},
+		{
+			"single record",
+			func(l *slog.Logger) {
+				l.Info("a")
+				l.Info("b")
+			},
+			[]string{"msg=a", "msg=b"},
+		},
+		{
+			"different attributes",
+			func(l *slog.Logger) {
+				l.Info("a", "n", 1)
+				l.Info("a", "n", 2)
+			},
+			[]string{"msg=a n=1", "msg=a n=2"},
+		},
+		{
+			"different levels",
+			func(l *slog.Logger) {
+				l.Info("a")
+				l.Warn("a")
+			},
+			[]string{"msg=a", "msg=a"},
+		},
+		{
+			"different logger attributes",
+			func(l *slog.Logger) {
+				l.With("svc", "a").Info("a")
+				l.With("svc", "b").Info("a")
+				l.With("svc", "b").Info("a")
+				l.Info("b")
+			},
+			[]string{"msg=a svc=a", "msg=a svc=b", "msg=a svc=b repeated=1", "msg=b"},
+		},
+		{
+			"different context attributes",
+			func(l *slog.Logger) {
+				l.InfoContext(ctx1, "a")
+				l.InfoContext(ctx2, "a")
+				l.InfoContext(ctx2, "a")
+				l.Info("b")
+			},
+			[]string{"msg=a request_id=r1", "msg=a request_id=r2", "msg=a request_id=r2 repeated=1", "msg=b"},
+		},
+		{
+			"error values resolved",
+			func(l *slog.Logger) {
+				l.Error("failed", Err(errors.New("boom")))
+				l.Error("failed", Err(errors.New("boom")))
+				l.Info("done")
+			},
+			[]string{"msg=failed error=boom", "msg=failed error=boom repeated=1", "msg=done"},
+		},
+		{
+			"records above error never collapsed",
+			func(l *slog.Logger) {
+				l.Error("a")
+				l.Error("a")
+				l.Log(context.Background(), LevelFatal, "f")
+				l.Log(context.Background(), LevelFatal, "f")
+			},
+			[]string{"msg=a", "msg=a repeated=1", "msg=f", "msg=f"},
+		},
+		{
+			"groups",
+			func(l *slog.Logger) {
+				l.Info("a", slog.Group("g", "n", 1))
+				l.Info("a", slog.Group("g", "n", 2))
+				l.Info("a", slog.Group("g", "n", 2))
+				l.Info("b")
+			},
+			[]string{"msg=a g.n=1", "msg=a g.n=2", "msg=a g.n=2 repeated=1", "msg=b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(slog.New(NewDedupHandler(NewContextHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: dropTimeAndLevel,
+			})))))
+
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDedupHandlerWindow(t *testing.T) {
+	var buf syncBuffer
+
+	logger := slog.New(NewDedupHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithDedupWindow(20*time.Millisecond)))
+
+	for i := 0; i < 3; i++ {
+		logger.Info("tick")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "repeated=2") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	logger.Info("tick")
+
+	if got, want := buf.String(), "msg=tick\nmsg=tick repeated=2\nmsg=tick\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestDedupHandlerFlush(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewDedupHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}))
+	logger := slog.New(h)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	h.Flush()
+	h.Flush()
+
+	if got, want := buf.String(), "msg=tick\nmsg=tick repeated=1\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// countingValuer counts how many times it is resolved
+type countingValuer struct {
+	n *int
+}
+
+func (v countingValuer) LogValue() slog.Value {
+	*v.n++
+
+	return slog.StringValue("lazy")
+}
+
+func TestDedupHandlerLogValuer(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger, v countingValuer)
+	}{
+		{"record attribute", func(l *slog.Logger, v countingValuer) { l.Info("a", "v", v) }},
+		{"attribute in group", func(l *slog.Logger, v countingValuer) { l.Info("a", slog.Group("g", "v", v)) }},
+		{"logger attribute", func(l *slog.Logger, v countingValuer) { l.With("v", v).Info("a") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				n   int
+			)
+
+			logger := slog.New(NewDedupHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel})))
+
+			tt.log(logger, countingValuer{&n})
+			tt.log(logger, countingValuer{&n})
+
+			if got := strings.Count(buf.String(), "lazy"); got != 2 {
+				t.Errorf("output = %q, want both records", buf.String())
+			}
+
+			if n != 2 {
+				t.Errorf("LogValue called %d times, want 2", n)
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use by the handler and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
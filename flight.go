@@ -0,0 +1,237 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+const (
+	// Default flight recorder configuration constants
+	defaultFlightSize       = 100
+	defaultFlightMinLevel   = LevelDebug
+	defaultFlightFlushLevel = LevelError
+
+	// FlightBackfillKey is the attribute key marking records logged after the fact
+	// by a flight recorder
+	FlightBackfillKey = "backfill"
+)
+
+// FlightRecorderOptions contains configuration for the flight recorder handler
+type FlightRecorderOptions struct {
+	Size       int   // Maximum number of records kept per scope, older ones are discarded
+	MinLevel   Level // Lowest level of the kept records
+	FlushLevel Level // Level of the records flushing the kept ones
+}
+
+// FlightRecorderOption defines a function type for configuring FlightRecorderOptions
+type FlightRecorderOption func(*FlightRecorderOptions)
+
+// newFlightRecorderOptions applies the options on top of the default configuration
+func newFlightRecorderOptions(opts ...FlightRecorderOption) *FlightRecorderOptions {
+	config := &FlightRecorderOptions{
+		Size:       defaultFlightSize,
+		MinLevel:   defaultFlightMinLevel,
+		FlushLevel: defaultFlightFlushLevel,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// flightKey is a private type used as unique context key to store flight recorder scopes
+type flightKey struct{}
+
+// flightEntry is a record kept by a flight recorder with what is needed to log it later
+type flightEntry struct {
+	ctx    context.Context
+	next   slog.Handler // handler, including With attributes and groups, the record was logged with
+	record slog.Record
+}
+
+// flightScope is the ring buffer of the records of one scope, such as a request
+type flightScope struct {
+	mu      sync.Mutex
+	entries []flightEntry
+	start   int // index of the oldest entry
+	count   int
+}
+
+// ContextWithFlightRecorder returns a derived context starting a flight recorder scope.
+// Records below the logger level logged with this context, or contexts derived from it,
+// are kept by [FlightRecorderHandler] until an error is logged in the scope. When the
+// scope ends without error, the kept records are discarded with the context.
+//
+// Parameters:
+//   - ctx: parent context
+//
+// Returns:
+//   - new context.Context with an empty scope
+//
+// Example:
+//
+//	ctx = ContextWithFlightRecorder(ctx)
+//	logger.DebugContext(ctx, "cache miss", "key", key) // kept in memory
+//	logger.ErrorContext(ctx, "load failed")           // logs "cache miss" first, with backfill=true
+func ContextWithFlightRecorder(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, flightKey{}, &flightScope{})
+}
+
+// flightScopeFromContext returns the flight recorder scope of the context, if any
+func flightScopeFromContext(ctx context.Context) *flightScope {
+	if ctx == nil {
+		return nil
+	}
+
+	scope, _ := ctx.Value(flightKey{}).(*flightScope)
+
+	return scope
+}
+
+// FlightRecorderHandler is a [slog.Handler] middleware keeping Debug detail in memory
+// while logging at a higher level. Records that the next handler does not enable,
+// logged within a scope started by [ContextWithFlightRecorder], are kept in a ring
+// buffer of the last [FlightRecorderOptions.Size] records of the scope.
+//
+// When a record at [FlightRecorderOptions.FlushLevel] or above is logged in the scope,
+// the kept records are logged first, in order and with their original time, marked
+// with the [FlightBackfillKey] attribute. Records logged without a scope are handled
+// as by the next handler.
+type FlightRecorderHandler struct {
+	next slog.Handler
+	opts *FlightRecorderOptions
+}
+
+// NewFlightRecorderHandler wraps a handler with a flight recorder
+// next: Handler receiving the records, whose level sets which records are kept
+// opts: Variadic list of configuration options
+// Returns: Flight recorder handler
+func NewFlightRecorderHandler(next slog.Handler, opts ...FlightRecorderOption) *FlightRecorderHandler {
+	return &FlightRecorderHandler{next: next, opts: newFlightRecorderOptions(opts...)}
+}
+
+// FlightRecorderMiddleware returns a middleware keeping records for errors, for use with [WithMiddleware]
+// opts: Variadic list of configuration options
+// Returns: Flight recorder middleware
+func FlightRecorderMiddleware(opts ...FlightRecorderOption) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewFlightRecorderHandler(next, opts...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *FlightRecorderHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.next.Enabled(ctx, level) {
+		return true
+	}
+
+	return level >= h.opts.MinLevel && flightScopeFromContext(ctx) != nil
+}
+
+// Handle implements [slog.Handler]
+func (h *FlightRecorderHandler) Handle(ctx context.Context, r slog.Record) error {
+	scope := flightScopeFromContext(ctx)
+
+	if !h.next.Enabled(ctx, r.Level) {
+		if scope != nil && r.Level >= h.opts.MinLevel {
+			scope.add(flightEntry{ctx: ctx, next: h.next, record: r.Clone()}, h.opts.Size)
+		}
+
+		return nil
+	}
+
+	if scope != nil && r.Level >= h.opts.FlushLevel {
+		for _, e := range scope.take() {
+			e.record.AddAttrs(Bool(FlightBackfillKey, true))
+			_ = e.next.Handle(e.ctx, e.record)
+		}
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *FlightRecorderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &FlightRecorderHandler{next: h.next.WithAttrs(attrs), opts: h.opts}
+}
+
+// WithGroup implements [slog.Handler]
+func (h *FlightRecorderHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &FlightRecorderHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// add keeps an entry, discarding the oldest one when size entries are kept
+func (s *flightScope) add(e flightEntry, size int) {
+	if size <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = make([]flightEntry, size)
+	}
+
+	s.entries[(s.start+s.count)%len(s.entries)] = e
+
+	if s.count < len(s.entries) {
+		s.count++
+	} else {
+		s.start = (s.start + 1) % len(s.entries)
+	}
+}
+
+// take returns the kept entries, oldest first, and empties the scope
+func (s *flightScope) take() []flightEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]flightEntry, s.count)
+	for i := range entries {
+		entries[i] = s.entries[(s.start+i)%len(s.entries)]
+		s.entries[(s.start+i)%len(s.entries)] = flightEntry{}
+	}
+
+	s.start, s.count = 0, 0
+
+	return entries
+}
+
+// WithFlightRecorderSize sets the maximum number of records kept per scope
+// size: Number of most recent records kept
+// Returns: Flight recorder option function
+func WithFlightRecorderSize(size int) FlightRecorderOption {
+	return func(o *FlightRecorderOptions) {
+		o.Size = size
+	}
+}
+
+// WithFlightRecorderMinLevel sets the lowest level of the kept records
+// level: Lowest kept level, Debug by default
+// Returns: Flight recorder option function
+func WithFlightRecorderMinLevel(level Level) FlightRecorderOption {
+	return func(o *FlightRecorderOptions) {
+		o.MinLevel = level
+	}
+}
+
+// WithFlightRecorderFlushLevel sets the level of the records flushing the kept ones
+// level: Lowest flushing level, Error by default
+// Returns: Flight recorder option function
+func WithFlightRecorderFlushLevel(level Level) FlightRecorderOption {
+	return func(o *FlightRecorderOptions) {
+		o.FlushLevel = level
+	}
+}
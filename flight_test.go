@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFlightRecorderHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []FlightRecorderOption
+		log  func(ctx context.Context, l *slog.Logger)
+		want []string
+	}{
+		{
+			"flushed on error",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.InfoContext(ctx, "b")
+				l.DebugContext(ctx, "c")
+				l.ErrorContext(ctx, "failed")
+			},
+			[]string{"level=INFO msg=b", "level=DEBUG msg=a backfill=true", "level=DEBUG msg=c backfill=true", "level=ERROR msg=failed"},
+		},
+		{
+			"discarded without error",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.WarnContext(ctx, "b")
+			},
+			[]string{"level=WARN msg=b"},
+		},
+		{
+			"flushed once",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.ErrorContext(ctx, "first")
+				l.ErrorContext(ctx, "second")
+			},
+			[]string{"level=DEBUG msg=a backfill=true", "level=ERROR msg=first", "level=ERROR msg=second"},
+		},
+		{
+			"last records kept",
+			[]FlightRecorderOption{WithFlightRecorderSize(2)},
+			func(ctx context.Context, l *slog.Logger) {
+				for _, msg := range []string{"a", "b", "c"} {
+					l.DebugContext(ctx, msg)
+				}
+
+				l.ErrorContext(ctx, "failed")
+			},
+			[]string{"level=DEBUG msg=b backfill=true", "level=DEBUG msg=c backfill=true", "level=ERROR msg=failed"},
+		},
+		{
+			"without scope",
+			nil,
+			func(_ context.Context, l *slog.Logger) {
+				l.Debug("a")
+				l.Error("failed")
+			},
+			[]string{"level=ERROR msg=failed"},
+		},
+		{
+			"other scope",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.ErrorContext(ContextWithFlightRecorder(context.Background()), "failed")
+			},
+			[]string{"level=ERROR msg=failed"},
+		},
+		{
+			"derived context",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ContextWithAttrs(ctx, String("step", "load")), "a")
+				l.ErrorContext(ctx, "failed")
+			},
+			[]string{"level=DEBUG msg=a step=load backfill=true", "level=ERROR msg=failed"},
+		},
+		{
+			"logger attributes and groups",
+			nil,
+			func(ctx context.Context, l *slog.Logger) {
+				l.With("k", "v").WithGroup("g").DebugContext(ctx, "a", "n", 1)
+				l.ErrorContext(ctx, "failed")
+			},
+			[]string{"level=DEBUG msg=a k=v g.n=1 g.backfill=true", "level=ERROR msg=failed"},
+		},
+		{
+			"min level",
+			[]FlightRecorderOption{WithFlightRecorderMinLevel(LevelInfo - 1)},
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.ErrorContext(ctx, "failed")
+			},
+			[]string{"level=ERROR msg=failed"},
+		},
+		{
+			"flush level",
+			[]FlightRecorderOption{WithFlightRecorderFlushLevel(LevelWarn)},
+			func(ctx context.Context, l *slog.Logger) {
+				l.DebugContext(ctx, "a")
+				l.WarnContext(ctx, "slow")
+			},
+			[]string{"level=DEBUG msg=a backfill=true", "level=WARN msg=slow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := NewLogger(WithWriter(&buf), WithLogLevel(LevelInfo), WithMiddleware(FlightRecorderMiddleware(tt.opts...)))
+			tt.log(ContextWithFlightRecorder(context.Background()), logger.Logger)
+
+			var got []string
+
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if line != "" {
+					got = append(got, line[strings.Index(line, " ")+1:]) // drop the time
+				}
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestFlightRecorderHandlerEnabled(t *testing.T) {
+	h := NewFlightRecorderHandler(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	scoped := ContextWithFlightRecorder(context.Background())
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		level Level
+		want  bool
+	}{
+		{"enabled by next", context.Background(), LevelInfo, true},
+		{"below level without scope", context.Background(), LevelDebug, false},
+		{"below level in scope", scoped, LevelDebug, true},
+		{"below min level in scope", scoped, LevelDebug - 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Enabled(tt.ctx, tt.level); got != tt.want {
+				t.Errorf("Enabled(%v) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+// Package grpclogging provides gRPC server and client interceptors for the logging package.
+// Server interceptors inject a request-scoped logger and request ID into the context;
+// all interceptors log the method, peer, status code, duration and, for streams,
+// message counts, and propagate request IDs via metadata.
+package grpclogging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/sergei-galichev/logging"
+)
+
+const (
+	// Default configuration constants
+	defaultServerMessage = "grpc server call"
+	defaultClientMessage = "grpc client call"
+)
+
+// Options contains configuration for the interceptors
+type Options struct {
+	LevelFunc func(code codes.Code) logging.Level // Maps status codes to the record level
+	RequestID []logging.RequestIDOption           // Metadata keys (header names) and ID generator
+	Message   string                              // Message of call records, defaults per side
+
+	requestID *logging.RequestIDOptions
+}
+
+// Option defines a function type for configuring Options
+type Option func(*Options)
+
+// newOptions applies the options on top of the default configuration
+func newOptions(message string, opts ...Option) *Options {
+	config := &Options{
+		LevelFunc: LevelForCode,
+		Message:   message,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	config.requestID = &logging.RequestIDOptions{
+		Headers:   []string{logging.DefaultRequestIDHeader},
+		Generator: logging.NewUUIDv7,
+		Validator: logging.ValidRequestID,
+	}
+
+	for _, opt := range config.RequestID {
+		opt(config.requestID)
+	}
+
+	return config
+}
+
+// LevelForCode maps gRPC codes to levels: client-caused codes are Info,
+// operational problems are Warn and server faults are Error
+// code: gRPC status code
+// Returns: Log level for the call record
+func LevelForCode(code codes.Code) logging.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return logging.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return logging.LevelWarn
+	}
+
+	return logging.LevelError
+}
+
+// UnaryServerInterceptor returns a server interceptor logging unary calls
+// logger: Logger used for call records; nil means [logging.LoggerFromContext]
+// opts: Variadic list of configuration options
+// Returns: gRPC unary server interceptor
+func UnaryServerInterceptor(logger *logging.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	config := newOptions(defaultServerMessage, opts...)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, reqLogger := config.serverContext(ctx, logger)
+
+		resp, err := handler(ctx, req)
+
+		config.log(ctx, reqLogger, info.FullMethod, peerAddr(ctx), start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor logging streaming calls
+// logger: Logger used for call records; nil means [logging.LoggerFromContext]
+// opts: Variadic list of configuration options
+// Returns: gRPC stream server interceptor
+func StreamServerInterceptor(logger *logging.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	config := newOptions(defaultServerMessage, opts...)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, reqLogger := config.serverContext(ss.Context(), logger)
+
+		stream := &serverStream{ServerStream: ss, ctx: ctx}
+		err := handler(srv, stream)
+
+		config.log(ctx, reqLogger, info.FullMethod, peerAddr(ctx), start, err,
+			slog.Int64("sent", stream.sent),
+			slog.Int64("received", stream.received),
+		)
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor logging unary calls through
+// [logging.LoggerFromContext] and forwarding the request ID via metadata
+// opts: Variadic list of configuration options
+// Returns: gRPC unary client interceptor
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	config := newOptions(defaultClientMessage, opts...)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx = config.outgoingContext(ctx)
+
+		var p peer.Peer
+
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+
+		config.log(ctx, logging.LoggerFromContext(ctx), method, addrString(&p), start, err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor logging streaming calls through
+// [logging.LoggerFromContext] and forwarding the request ID via metadata.
+// The record is written when the stream ends: when RecvMsg returns an error or io.EOF,
+// after the single response of client-streaming calls, or when the context is done.
+// opts: Variadic list of configuration options
+// Returns: gRPC stream client interceptor
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	config := newOptions(defaultClientMessage, opts...)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx = config.outgoingContext(ctx)
+
+		p := &peer.Peer{}
+
+		cs, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			config.log(ctx, logging.LoggerFromContext(ctx), method, addrString(p), start, err)
+
+			return nil, err
+		}
+
+		// p is filled when the stream finishes, possibly concurrently with the
+		// cancellation report, so the peer of established streams comes from their context
+		addr := peerAddr(cs.Context())
+
+		stream := &clientStream{
+			ClientStream: cs,
+			desc:         desc,
+			done: func(sent, received int64, err error) {
+				config.log(ctx, logging.LoggerFromContext(ctx), method, addr, start, err,
+					slog.Int64("sent", sent),
+					slog.Int64("received", received),
+				)
+			},
+		}
+
+		// streams abandoned by the caller end with the cancellation of their context
+		stream.stop = context.AfterFunc(ctx, func() {
+			stream.finish(status.FromContextError(ctx.Err()).Err())
+		})
+
+		return stream, nil
+	}
+}
+
+// serverContext stores the request ID and a request-scoped logger in the context
+func (o *Options) serverContext(ctx context.Context, logger *logging.Logger) (context.Context, *logging.Logger) {
+	id := o.incomingRequestID(ctx)
+	ctx = logging.ContextWithRequestID(ctx, id)
+
+	if header := o.metadataKey(); header != "" {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(header, id))
+	}
+
+	if logger == nil {
+		logger = logging.LoggerFromContext(ctx)
+	}
+
+	reqLogger := &logging.Logger{Logger: logger.With(slog.String(logging.RequestIDKey, id))}
+
+	return logging.ContextWithLogger(ctx, reqLogger), reqLogger
+}
+
+// incomingRequestID reads a valid request ID from incoming metadata or generates a new one
+func (o *Options) incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range o.requestID.Headers {
+			if values := md.Get(key); len(values) > 0 && o.requestID.Valid(values[0]) {
+				return values[0]
+			}
+		}
+	}
+
+	return o.requestID.Generator()
+}
+
+// outgoingContext forwards the request ID stored in the context via outgoing metadata
+func (o *Options) outgoingContext(ctx context.Context) context.Context {
+	id := logging.RequestIDFromContext(ctx)
+	key := o.metadataKey()
+
+	if id == "" || key == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, key, id)
+}
+
+// metadataKey returns the metadata key used to write request IDs
+func (o *Options) metadataKey() string {
+	if len(o.requestID.Headers) == 0 {
+		return ""
+	}
+
+	return o.requestID.Headers[0]
+}
+
+// log writes the call record
+func (o *Options) log(ctx context.Context, logger *logging.Logger, method, peer string, start time.Time, err error, extra ...slog.Attr) {
+	code := status.Code(err)
+	service, name := path.Split(method)
+
+	attrs := make([]slog.Attr, 0, 7+len(extra))
+	attrs = append(attrs,
+		slog.String("method", method),
+		slog.String("service", path.Clean(service)[1:]),
+		slog.String("rpc", name),
+		slog.String("peer", peer),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+	attrs = append(attrs, extra...)
+
+	if err != nil {
+		attrs = append(attrs, logging.Err(err))
+	}
+
+	logger.LogAttrs(ctx, o.LevelFunc(code), o.Message, attrs...)
+}
+
+// peerAddr returns the remote address stored in a server context
+func peerAddr(ctx context.Context) string {
+	p, _ := peer.FromContext(ctx)
+
+	return addrString(p)
+}
+
+// addrString returns the peer address or an empty string
+func addrString(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+
+	return p.Addr.String()
+}
+
+// serverStream overrides the context and counts messages
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     int64
+	received int64
+}
+
+// Context implements [grpc.ServerStream]
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg implements [grpc.ServerStream]
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+
+	return err
+}
+
+// RecvMsg implements [grpc.ServerStream]
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+
+	return err
+}
+
+// clientStream counts messages and reports once when the stream ends.
+// gRPC allows SendMsg and RecvMsg to run concurrently, hence the atomic counters.
+type clientStream struct {
+	grpc.ClientStream
+	desc     *grpc.StreamDesc
+	sent     atomic.Int64
+	received atomic.Int64
+	once     sync.Once
+	stop     func() bool // stops the report on context cancellation, set before the stream is returned
+	done     func(sent, received int64, err error)
+}
+
+// SendMsg implements [grpc.ClientStream]
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+
+	return err
+}
+
+// RecvMsg implements [grpc.ClientStream]
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	switch {
+	case err == nil:
+		s.received.Add(1)
+
+		// without server streaming, the single response ends the call
+		if !s.desc.ServerStreams {
+			s.end(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	default:
+		s.end(err)
+	}
+
+	return err
+}
+
+// end reports the end of the stream seen by RecvMsg and stops watching the context
+func (s *clientStream) end(err error) {
+	s.stop()
+	s.finish(err)
+}
+
+// finish reports the end of the stream the first time it is called
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		s.done(s.sent.Load(), s.received.Load(), err)
+	})
+}
+
+// WithLevelFunc sets the function mapping status codes to levels
+// fn: Mapping function, [LevelForCode] by default
+// Returns: Configuration option function
+func WithLevelFunc(fn func(code codes.Code) logging.Level) Option {
+	return func(o *Options) {
+		o.LevelFunc = fn
+	}
+}
+
+// WithRequestID sets the request ID metadata keys and generator.
+// Header names are used as metadata keys, "x-request-id" by default.
+// opts: Request ID options
+// Returns: Configuration option function
+func WithRequestID(opts ...logging.RequestIDOption) Option {
+	return func(o *Options) {
+		o.RequestID = append(o.RequestID, opts...)
+	}
+}
+
+// WithMessage sets the message of call records
+// message: Record message
+// Returns: Configuration option function
+func WithMessage(message string) Option {
+	return func(o *Options) {
+		o.Message = message
+	}
+}
@@ -0,0 +1,458 @@
+package grpclogging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// echoStreamDesc describes bidirectional, client-streaming and server-streaming
+// methods registered without generated code
+var echoStreamDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Stream",
+		ServerStreams: true,
+		ClientStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			for {
+				var msg wrapperspb.StringValue
+				if err := stream.RecvMsg(&msg); err != nil {
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+
+					return err
+				}
+
+				logging.LoggerFromContext(stream.Context()).InfoContext(stream.Context(), "echo")
+
+				if err := stream.SendMsg(&msg); err != nil {
+					return err
+				}
+			}
+		},
+	}, {
+		StreamName:    "Collect",
+		ClientStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			var joined string
+
+			for {
+				var msg wrapperspb.StringValue
+				if err := stream.RecvMsg(&msg); err != nil {
+					if errors.Is(err, io.EOF) {
+						return stream.SendMsg(wrapperspb.String(joined))
+					}
+
+					return err
+				}
+
+				joined += msg.GetValue()
+			}
+		},
+	}, {
+		StreamName:    "Repeat",
+		ServerStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			var msg wrapperspb.StringValue
+			if err := stream.RecvMsg(&msg); err != nil {
+				return err
+			}
+
+			for range 3 {
+				if err := stream.SendMsg(&msg); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}},
+}
+
+// newTestLogger returns a JSON logger writing into buf
+func newTestLogger(buf *bytes.Buffer) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(logging.NewContextHandler(slog.NewJSONHandler(buf, nil)))}
+}
+
+// decodeRecords parses JSON lines written by the test logger
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+// startServer runs a server with the interceptors on an in-memory listener
+func startServer(t *testing.T, serverLog *bytes.Buffer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	logger := newTestLogger(serverLog)
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(logger)),
+		grpc.StreamInterceptor(StreamServerInterceptor(logger)),
+	)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("svc", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	srv.RegisterService(&echoStreamDesc, nil)
+
+	go func() { _ = srv.Serve(lis) }()
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   string
+		wantCode  string
+		wantLevel string
+	}{
+		{"ok", "svc", "OK", "INFO"},
+		{"not found", "unknown", "NotFound", "INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var serverLog, clientLog bytes.Buffer
+
+			conn := startServer(t, &serverLog)
+
+			ctx := logging.ContextWithLogger(context.Background(), newTestLogger(&clientLog))
+			ctx = logging.ContextWithRequestID(ctx, "req-1")
+
+			var header metadata.MD
+
+			_, _ = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: tt.service}, grpc.Header(&header))
+
+			if got := header.Get("x-request-id"); len(got) != 1 || got[0] != "req-1" {
+				t.Errorf("response header x-request-id = %v, want req-1", got)
+			}
+
+			for side, buf := range map[string]*bytes.Buffer{"server": &serverLog, "client": &clientLog} {
+				records := decodeRecords(t, buf)
+				if len(records) != 1 {
+					t.Fatalf("%s records = %d, want 1", side, len(records))
+				}
+
+				rec := records[0]
+				if rec["code"] != tt.wantCode || rec["level"] != tt.wantLevel || rec["request_id"] != "req-1" {
+					t.Errorf("%s record = %v, want code %s, level %s and request_id", side, rec, tt.wantCode, tt.wantLevel)
+				}
+
+				if rec["method"] != "/grpc.health.v1.Health/Check" || rec["service"] != "grpc.health.v1.Health" || rec["rpc"] != "Check" {
+					t.Errorf("%s record = %v, want method details", side, rec)
+				}
+
+				if rec["peer"] == "" {
+					t.Errorf("%s record = %v, want peer", side, rec)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	var serverLog, clientLog bytes.Buffer
+
+	conn := startServer(t, &serverLog)
+
+	ctx := logging.ContextWithLogger(context.Background(), newTestLogger(&clientLog))
+
+	stream, err := conn.NewStream(ctx, &echoStreamDesc.Streams[0], "/test.Echo/Stream")
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+
+	for _, s := range []string{"a", "b"} {
+		if err := stream.SendMsg(wrapperspb.String(s)); err != nil {
+			t.Fatalf("SendMsg() error = %v", err)
+		}
+
+		var reply wrapperspb.StringValue
+		if err := stream.RecvMsg(&reply); err != nil {
+			t.Fatalf("RecvMsg() error = %v", err)
+		}
+	}
+
+	_ = stream.CloseSend()
+
+	var reply wrapperspb.StringValue
+	if err := stream.RecvMsg(&reply); !errors.Is(err, io.EOF) {
+		t.Fatalf("RecvMsg() error = %v, want io.EOF", err)
+	}
+
+	serverRecords := decodeRecords(t, &serverLog)
+	if len(serverRecords) != 3 {
+		t.Fatalf("server records = %d, want 2 handler records and 1 call record", len(serverRecords))
+	}
+
+	requestID := serverRecords[0]["request_id"]
+	if requestID == nil || serverRecords[2]["request_id"] != requestID {
+		t.Errorf("server records = %v, want a generated request_id on every record", serverRecords)
+	}
+
+	for side, rec := range map[string]map[string]any{"server": serverRecords[2], "client": decodeRecords(t, &clientLog)[0]} {
+		if rec["code"] != "OK" || rec["sent"] != float64(2) || rec["received"] != float64(2) {
+			t.Errorf("%s record = %v, want code OK and 2 messages each way", side, rec)
+		}
+	}
+}
+
+func TestServerInvalidRequestID(t *testing.T) {
+	var serverLog bytes.Buffer
+
+	conn := startServer(t, &serverLog)
+
+	forged := `req-1" level=ERROR`
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", forged)
+
+	var header metadata.MD
+
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	got := header.Get("x-request-id")
+	if len(got) != 1 || got[0] == forged || !logging.ValidRequestID(got[0]) {
+		t.Errorf("response header x-request-id = %q, want a generated ID", got)
+	}
+
+	if rec := decodeRecords(t, &serverLog)[0]; len(got) == 1 && rec["request_id"] != got[0] {
+		t.Errorf("server record = %v, want request_id %s", rec, got[0])
+	}
+}
+
+func TestLevelForCode(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want logging.Level
+	}{
+		{codes.OK, logging.LevelInfo},
+		{codes.NotFound, logging.LevelInfo},
+		{codes.Unavailable, logging.LevelWarn},
+		{codes.DeadlineExceeded, logging.LevelWarn},
+		{codes.Internal, logging.LevelError},
+		{codes.Unknown, logging.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := LevelForCode(tt.code); got != tt.want {
+				t.Errorf("LevelForCode(%v) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptorCallTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		stream       int
+		call         func(t *testing.T, stream grpc.ClientStream)
+		wantSent     float64
+		wantReceived float64
+	}{
+		{
+			"client streaming",
+			1,
+			func(t *testing.T, stream grpc.ClientStream) {
+				for _, s := range []string{"a", "b"} {
+					if err := stream.SendMsg(wrapperspb.String(s)); err != nil {
+						t.Fatalf("SendMsg() error = %v", err)
+					}
+				}
+
+				_ = stream.CloseSend()
+
+				var reply wrapperspb.StringValue
+				if err := stream.RecvMsg(&reply); err != nil || reply.GetValue() != "ab" {
+					t.Fatalf("RecvMsg() = %q, %v, want ab", reply.GetValue(), err)
+				}
+			},
+			2,
+			1,
+		},
+		{
+			"server streaming",
+			2,
+			func(t *testing.T, stream grpc.ClientStream) {
+				if err := stream.SendMsg(wrapperspb.String("a")); err != nil {
+					t.Fatalf("SendMsg() error = %v", err)
+				}
+
+				_ = stream.CloseSend()
+
+				for {
+					var reply wrapperspb.StringValue
+					if err := stream.RecvMsg(&reply); err != nil {
+						if !errors.Is(err, io.EOF) {
+							t.Fatalf("RecvMsg() error = %v", err)
+						}
+
+						return
+					}
+				}
+			},
+			1,
+			3,
+		},
+		{
+			"concurrent send and receive",
+			0,
+			func(t *testing.T, stream grpc.ClientStream) {
+				go func() {
+					for range 10 {
+						_ = stream.SendMsg(wrapperspb.String("a"))
+					}
+
+					_ = stream.CloseSend()
+				}()
+
+				for {
+					var reply wrapperspb.StringValue
+					if err := stream.RecvMsg(&reply); err != nil {
+						return
+					}
+				}
+			},
+			10,
+			10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var serverLog bytes.Buffer
+
+			clientLog := &syncBuffer{}
+			conn := startServer(t, &serverLog)
+			ctx := logging.ContextWithLogger(context.Background(), &logging.Logger{Logger: slog.New(slog.NewJSONHandler(clientLog, nil))})
+
+			desc := &echoStreamDesc.Streams[tt.stream]
+
+			stream, err := conn.NewStream(ctx, desc, "/test.Echo/"+desc.StreamName)
+			if err != nil {
+				t.Fatalf("NewStream() error = %v", err)
+			}
+
+			tt.call(t, stream)
+
+			records := clientLog.records(t)
+			if len(records) != 1 {
+				t.Fatalf("client records = %v, want 1", records)
+			}
+
+			if rec := records[0]; rec["code"] != "OK" || rec["sent"] != tt.wantSent || rec["received"] != tt.wantReceived {
+				t.Errorf("client record = %v, want code OK, %v sent and %v received", rec, tt.wantSent, tt.wantReceived)
+			}
+		})
+	}
+}
+
+func TestStreamClientInterceptorAbandoned(t *testing.T) {
+	var serverLog bytes.Buffer
+
+	clientLog := &syncBuffer{}
+	conn := startServer(t, &serverLog)
+
+	ctx, cancel := context.WithCancel(logging.ContextWithLogger(context.Background(),
+		&logging.Logger{Logger: slog.New(slog.NewJSONHandler(clientLog, nil))}))
+
+	stream, err := conn.NewStream(ctx, &echoStreamDesc.Streams[0], "/test.Echo/Stream")
+	if err != nil {
+		t.Fatalf("NewStream() error = %v", err)
+	}
+
+	if err := stream.SendMsg(wrapperspb.String("a")); err != nil {
+		t.Fatalf("SendMsg() error = %v", err)
+	}
+
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(clientLog.records(t)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	records := clientLog.records(t)
+	if len(records) != 1 || records[0]["code"] != "Canceled" || records[0]["sent"] != float64(1) {
+		t.Errorf("client records = %v, want one Canceled record with 1 message sent", records)
+	}
+}
+
+// syncBuffer collects records written concurrently with the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// records decodes the records written so far
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	buf := bytes.NewBuffer(b.buf.Bytes())
+
+	return decodeRecords(t, buf)
+}
@@ -0,0 +1,307 @@
+// Package httplog provides net/http integrations for the logging package:
+// a server middleware writing one access record per request and a client
+// RoundTripper logging outbound calls.
+package httplog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/sergei-galichev/logging"
+)
+
+const (
+	// Default configuration constants
+	defaultSampleRate = 1.0
+	defaultMessage    = "http request"
+)
+
+// Options contains configuration for the server middleware
+type Options struct {
+	SkipPaths      []string                       // Paths that are not logged, such as health checks
+	SampleRate     float64                        // Fraction of successful (1xx-3xx) requests to log
+	RemoteIPHeader string                         // Header with the client IP set by a trusted proxy
+	RequestID      []logging.RequestIDOption      // Request ID header and generator options
+	Message        string                         // Message of access records
+	LevelFunc      func(status int) logging.Level // Maps response status to the record level
+	FlightRecorder bool                           // Whether each request starts a flight recorder scope
+}
+
+// Option defines a function type for configuring Options
+type Option func(*Options)
+
+// Middleware returns a net/http middleware for the given logger.
+// For every request it stores a request ID (see [logging.EnsureRequestID]) and a
+// request-scoped logger (see [logging.ContextWithLogger]) in the request context,
+// echoes the request ID in the response, recovers panics into Error records with
+// a stack trace and writes one access record with method, route, status, bytes,
+// duration, remote IP and user agent.
+// logger: Logger used for access records; nil means [logging.LoggerFromContext]
+// opts: Variadic list of configuration options
+// Returns: Middleware wrapping an http.Handler
+func Middleware(logger *logging.Logger, opts ...Option) func(http.Handler) http.Handler {
+	config := &Options{
+		SampleRate: defaultSampleRate,
+		Message:    defaultMessage,
+		LevelFunc:  LevelForStatus,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	skip := make(map[string]struct{}, len(config.SkipPaths))
+	for _, p := range config.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := skip[r.URL.Path]; ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			start := time.Now()
+
+			ctx, id := logging.EnsureRequestID(r, config.RequestID...)
+			logging.SetRequestIDHeader(ctx, w.Header(), config.RequestID...)
+
+			base := logger
+			if base == nil {
+				base = logging.LoggerFromContext(r.Context())
+			}
+
+			reqLogger := &logging.Logger{Logger: base.With(slog.String(logging.RequestIDKey, id))}
+			ctx = logging.ContextWithLogger(ctx, reqLogger)
+
+			if config.FlightRecorder {
+				ctx = logging.ContextWithFlightRecorder(ctx)
+			}
+
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w}
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					if rec == http.ErrAbortHandler {
+						panic(rec)
+					}
+
+					reqLogger.ErrorContext(ctx, "http handler panic",
+						slog.String("panic", fmt.Sprint(rec)),
+						slog.String("stack", string(debug.Stack())),
+					)
+
+					if !rw.wroteHeader {
+						rw.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+
+				status := rw.statusCode()
+				if status < http.StatusBadRequest && config.SampleRate < 1 && rand.Float64() >= config.SampleRate {
+					return
+				}
+
+				reqLogger.LogAttrs(ctx, config.LevelFunc(status), config.Message,
+					slog.String("method", r.Method),
+					slog.String("route", route(r)),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int64("bytes", rw.bytes),
+					slog.Duration("duration", time.Since(start)),
+					slog.String("remote_ip", remoteIP(r, config.RemoteIPHeader)),
+					slog.String("user_agent", r.UserAgent()),
+				)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// LevelForStatus maps 5xx responses to Error, 4xx to Warn and everything else to Info
+// status: HTTP response status code
+// Returns: Log level for the access record
+func LevelForStatus(status int) logging.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logging.LevelError
+	case status >= http.StatusBadRequest:
+		return logging.LevelWarn
+	}
+
+	return logging.LevelInfo
+}
+
+// route returns the matched ServeMux pattern or the path if no pattern matched
+func route(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+
+	return r.URL.Path
+}
+
+// remoteIP returns the client IP from the trusted header or the connection address
+func remoteIP(r *http.Request, header string) string {
+	if header != "" {
+		if v := r.Header.Get(header); v != "" {
+			ip, _, _ := strings.Cut(v, ",")
+
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// responseWriter records the status code and the number of bytes written
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader implements [http.ResponseWriter]. Informational 1xx headers
+// other than 101 Switching Protocols can precede the final status and are not recorded.
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader && (status >= http.StatusOK || status == http.StatusSwitchingProtocols) {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements [http.ResponseWriter]
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+// Flush implements [http.Flusher]
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// ReadFrom implements [io.ReaderFrom], keeping the sendfile path of the underlying writer
+func (w *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := io.Copy(w.ResponseWriter, src)
+	w.bytes += n
+
+	return n, err
+}
+
+// Hijack implements [http.Hijacker], recording 101 Switching Protocols when no
+// status was written, as for WebSocket upgrades
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap allows [http.ResponseController] to reach the underlying writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the written status or 200 if the handler wrote nothing
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// WithSkipPaths sets request paths that are not logged, such as health checks
+// paths: Exact URL paths like "/healthz"
+// Returns: Configuration option function
+func WithSkipPaths(paths ...string) Option {
+	return func(o *Options) {
+		o.SkipPaths = append(o.SkipPaths, paths...)
+	}
+}
+
+// WithSampleRate sets the fraction of successful requests that are logged.
+// Requests with 4xx and 5xx responses are always logged.
+// rate: Value within [0, 1]; values outside the range are clamped
+// Returns: Configuration option function
+func WithSampleRate(rate float64) Option {
+	return func(o *Options) {
+		o.SampleRate = min(max(rate, 0), 1)
+	}
+}
+
+// WithRemoteIPHeader sets a header, set by a trusted proxy, that carries the client IP.
+// The first entry of comma-separated lists such as X-Forwarded-For is used.
+// header: Header name
+// Returns: Configuration option function
+func WithRemoteIPHeader(header string) Option {
+	return func(o *Options) {
+		o.RemoteIPHeader = header
+	}
+}
+
+// WithRequestID sets the request ID header and generator options
+// opts: Request ID options
+// Returns: Configuration option function
+func WithRequestID(opts ...logging.RequestIDOption) Option {
+	return func(o *Options) {
+		o.RequestID = append(o.RequestID, opts...)
+	}
+}
+
+// WithLevelFunc sets the function mapping the response status to a level
+// fn: Mapping function, [LevelForStatus] by default
+// Returns: Configuration option function
+func WithLevelFunc(fn func(status int) logging.Level) Option {
+	return func(o *Options) {
+		o.LevelFunc = fn
+	}
+}
+
+// WithFlightRecorder starts a flight recorder scope (see [logging.ContextWithFlightRecorder])
+// for each request, so that a [logging.FlightRecorderHandler] logs the Debug records of
+// requests that fail
+// Returns: Configuration option function
+func WithFlightRecorder() Option {
+	return func(o *Options) {
+		o.FlightRecorder = true
+	}
+}
@@ -0,0 +1,267 @@
+package httplog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// newTestLogger returns a JSON logger writing into buf
+func newTestLogger(buf *bytes.Buffer) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(logging.NewContextHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: logging.LevelDebug})))}
+}
+
+// decodeRecords parses JSON lines written by the test logger
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logging.LoggerFromContext(r.Context()).InfoContext(r.Context(), "inside handler")
+		_, _ = w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("GET /missing", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantLevel   string
+		wantRecords int
+	}{
+		{"success", "/users/42", http.StatusOK, "INFO", 2},
+		{"client error", "/missing", http.StatusNotFound, "WARN", 1},
+		{"panic", "/panic", http.StatusInternalServerError, "ERROR", 2},
+		{"skipped path", "/healthz", http.StatusNoContent, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			h := Middleware(newTestLogger(&buf), WithSkipPaths("/healthz"))(mux)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			req.Header.Set("User-Agent", "test-agent")
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			records := decodeRecords(t, &buf)
+			if len(records) != tt.wantRecords {
+				t.Fatalf("records = %d, want %d: %s", len(records), tt.wantRecords, buf.String())
+			}
+
+			if tt.wantRecords == 0 {
+				return
+			}
+
+			for _, r := range records {
+				if r["request_id"] != "req-1" {
+					t.Errorf("record %v, want request_id=req-1", r)
+				}
+			}
+
+			access := records[len(records)-1]
+			if access["level"] != tt.wantLevel || access["status"] != float64(tt.wantStatus) {
+				t.Errorf("access record = %v, want level %s and status %d", access, tt.wantLevel, tt.wantStatus)
+			}
+
+			if access["method"] != "GET" || access["user_agent"] != "test-agent" || access["remote_ip"] != "192.0.2.1" {
+				t.Errorf("access record = %v, want method, user agent and remote IP", access)
+			}
+
+			if rec.Header().Get("X-Request-ID") != "req-1" {
+				t.Errorf("response X-Request-ID = %q, want req-1", rec.Header().Get("X-Request-ID"))
+			}
+		})
+	}
+}
+
+func TestMiddlewareRouteAndBytes(t *testing.T) {
+	var buf bytes.Buffer
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	rec := httptest.NewRecorder()
+	Middleware(newTestLogger(&buf))(mux).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	access := decodeRecords(t, &buf)[0]
+
+	if access["route"] != "GET /users/{id}" || access["path"] != "/users/42" || access["bytes"] != float64(5) {
+		t.Errorf("access record = %v, want route, path and bytes", access)
+	}
+}
+
+// hijackRecorder is a ResponseRecorder supporting connection hijacking
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, peer := net.Pipe()
+	_ = peer.Close()
+
+	return conn, bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)), nil
+}
+
+func TestMiddlewareResponseWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBytes  int
+	}{
+		{
+			"informational header before final status",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusEarlyHints)
+				w.WriteHeader(http.StatusNotFound)
+			},
+			http.StatusNotFound,
+			0,
+		},
+		{
+			"reader from",
+			func(w http.ResponseWriter, _ *http.Request) {
+				rf, ok := w.(io.ReaderFrom)
+				if !ok {
+					t.Fatal("response writer does not implement io.ReaderFrom")
+				}
+
+				_, _ = rf.ReadFrom(strings.NewReader("hello"))
+			},
+			http.StatusOK,
+			5,
+		},
+		{
+			"hijacker",
+			func(w http.ResponseWriter, _ *http.Request) {
+				hj, ok := w.(http.Hijacker)
+				if !ok {
+					t.Fatal("response writer does not implement http.Hijacker")
+				}
+
+				conn, _, err := hj.Hijack()
+				if err != nil {
+					t.Fatalf("Hijack() error = %v", err)
+				}
+
+				_ = conn.Close()
+			},
+			http.StatusSwitchingProtocols,
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			rec := hijackRecorder{httptest.NewRecorder()}
+			Middleware(newTestLogger(&buf))(tt.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			access := decodeRecords(t, &buf)[0]
+			if access["status"] != float64(tt.wantStatus) || access["bytes"] != float64(tt.wantBytes) {
+				t.Errorf("access record = %v, want status %d and %d bytes", access, tt.wantStatus, tt.wantBytes)
+			}
+		})
+	}
+}
+
+func TestMiddlewareSampling(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Middleware(newTestLogger(&buf), WithSampleRate(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	for range 10 {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 1 || records[0]["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("records = %v, want only the failed request", records)
+	}
+}
+
+func TestMiddlewareFlightRecorder(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := logging.NewLogger(
+		logging.WithWriter(&buf),
+		logging.WithJSONFormat(true),
+		logging.WithLogLevel(logging.LevelInfo),
+		logging.WithMiddleware(logging.FlightRecorderMiddleware()),
+	)
+
+	h := Middleware(logger, WithFlightRecorder())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.LoggerFromContext(r.Context()).DebugContext(r.Context(), "step", "path", r.URL.Path)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("records = %v, want two access records and one backfilled step", records)
+	}
+
+	if step := records[1]; step["msg"] != "step" || step["path"] != "/fail" || step[logging.FlightBackfillKey] != true ||
+		step[logging.RequestIDKey] != records[2][logging.RequestIDKey] {
+		t.Errorf("backfilled record = %v, want the step of the failed request", step)
+	}
+}
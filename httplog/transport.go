@@ -0,0 +1,231 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sergei-galichev/logging"
+)
+
+const (
+	// Default transport configuration constants
+	defaultBodyLimit     = 0
+	defaultClientMessage = "http client request"
+	defaultBodyMessage   = "http client body"
+	redactedQueryValue   = "REDACTED"
+)
+
+// attemptKey is a private type used as unique context key to store retry attempts
+type attemptKey struct{}
+
+// TransportOptions contains configuration for the client RoundTripper
+type TransportOptions struct {
+	BodyLimit    int                            // Maximum body bytes captured at Debug level, 0 disables capture
+	AllowedQuery map[string]struct{}            // Query parameters logged in clear
+	Message      string                         // Message of request records
+	LevelFunc    func(status int) logging.Level // Maps response status to the record level
+}
+
+// TransportOption defines a function type for configuring TransportOptions
+type TransportOption func(*TransportOptions)
+
+// Transport is an [http.RoundTripper] that logs outbound requests and responses
+// through the logger stored in the request context (see [logging.LoggerFromContext]).
+type Transport struct {
+	base http.RoundTripper
+	opts *TransportOptions
+}
+
+// NewTransport wraps a RoundTripper with logging
+// base: Underlying RoundTripper; nil means http.DefaultTransport
+// opts: Variadic list of configuration options
+// Returns: Logging RoundTripper
+func NewTransport(base http.RoundTripper, opts ...TransportOption) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	config := &TransportOptions{
+		BodyLimit:    defaultBodyLimit,
+		AllowedQuery: make(map[string]struct{}),
+		Message:      defaultClientMessage,
+		LevelFunc:    LevelForStatus,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &Transport{
+		base: base,
+		opts: config,
+	}
+}
+
+// ContextWithAttempt stores the retry attempt number of an outbound request.
+// Retrying clients set it before each call so that records include the attempt.
+//
+// Parameters:
+//   - ctx: parent context
+//   - attempt: attempt number starting from 1
+//
+// Returns:
+//   - new context.Context containing the attempt number
+func ContextWithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFromContext returns the attempt stored by ContextWithAttempt or 1
+func attemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+
+	return 1
+}
+
+// RoundTrip implements [http.RoundTripper]
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logger := logging.LoggerFromContext(ctx)
+
+	captureBody := t.opts.BodyLimit > 0 && logger.Enabled(ctx, logging.LevelDebug)
+
+	var reqBody []byte
+
+	if captureBody && req.Body != nil && req.Body != http.NoBody {
+		// RoundTrippers must not modify the original request
+		req = req.Clone(ctx)
+		reqBody, req.Body = peekBody(req.Body, t.opts.BodyLimit)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", t.redactURL(req.URL)),
+		slog.Int("attempt", attemptFromContext(ctx)),
+		slog.Duration("duration", duration),
+	}
+
+	if err != nil {
+		attrs = append(attrs, logging.Err(err))
+		logger.LogAttrs(ctx, logging.LevelError, t.opts.Message, attrs...)
+
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	logger.LogAttrs(ctx, t.opts.LevelFunc(resp.StatusCode), t.opts.Message, attrs...)
+
+	if captureBody {
+		var respBody []byte
+		if resp.Body != nil {
+			respBody, resp.Body = peekBody(resp.Body, t.opts.BodyLimit)
+		}
+
+		logger.LogAttrs(ctx, logging.LevelDebug, defaultBodyMessage,
+			slog.String("method", req.Method),
+			slog.String("url", t.redactURL(req.URL)),
+			bodyAttr("request_body", reqBody, t.opts.BodyLimit),
+			bodyAttr("response_body", respBody, t.opts.BodyLimit),
+		)
+	}
+
+	return resp, nil
+}
+
+// redactURL drops the userinfo and replaces the values of query parameters
+// that are not allowed
+func (t *Transport) redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+
+	if u.RawQuery == "" {
+		return redacted.String()
+	}
+
+	query := u.Query()
+
+	for key, values := range query {
+		if _, ok := t.opts.AllowedQuery[key]; ok {
+			continue
+		}
+
+		for i := range values {
+			values[i] = redactedQueryValue
+		}
+	}
+
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+// peekBody reads up to limit+1 bytes from body and returns them together
+// with a reader that yields the full original content
+func peekBody(body io.ReadCloser, limit int) ([]byte, io.ReadCloser) {
+	buf, _ := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+
+	return buf, readCloser{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		Closer: body,
+	}
+}
+
+// bodyAttr logs a captured body, marking it when it exceeds the limit
+func bodyAttr(key string, body []byte, limit int) slog.Attr {
+	if body == nil {
+		return slog.String(key, "")
+	}
+
+	if len(body) > limit {
+		return slog.String(key, string(body[:limit])+"... (truncated)")
+	}
+
+	return slog.String(key, string(body))
+}
+
+// readCloser combines a reader with the closer of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// WithBodyCapture enables capturing request and response bodies at Debug level.
+// Response bodies are read eagerly up to the limit, so avoid it for streaming responses.
+// maxBytes: Maximum number of bytes logged per body, 0 disables capture
+// Returns: Transport option function
+func WithBodyCapture(maxBytes int) TransportOption {
+	return func(o *TransportOptions) {
+		o.BodyLimit = maxBytes
+	}
+}
+
+// WithAllowedQueryParams sets query parameters whose values are logged in clear.
+// All other query values are replaced with "REDACTED".
+// names: Query parameter names
+// Returns: Transport option function
+func WithAllowedQueryParams(names ...string) TransportOption {
+	return func(o *TransportOptions) {
+		for _, name := range names {
+			o.AllowedQuery[name] = struct{}{}
+		}
+	}
+}
+
+// WithTransportLevelFunc sets the function mapping the response status to a level
+// fn: Mapping function, [LevelForStatus] by default
+// Returns: Transport option function
+func WithTransportLevelFunc(fn func(status int) logging.Level) TransportOption {
+	return func(o *TransportOptions) {
+		o.LevelFunc = fn
+	}
+}
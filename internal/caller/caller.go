@@ -0,0 +1,44 @@
+// Package caller finds the source location of records logged through
+// third-party logging APIs bridged into the logging package.
+package caller
+
+import (
+	"runtime"
+	"strings"
+)
+
+// maxDepth is the number of frames searched for a caller outside the bridged packages
+const maxDepth = 32
+
+// PC returns the program counter of the first caller whose function does not start
+// with one of the prefixes, or 0 if there is none within the searched frames.
+// The result is a return address as produced by runtime.Callers, which is what
+// slog.NewRecord expects, not the frame.PC of runtime.Frame.
+// skip: Number of frames to skip, 0 identifying the caller of PC
+// prefixes: Function name prefixes of the frames to skip, such as "log."
+// Returns: Program counter for slog.NewRecord
+func PC(skip int, prefixes ...string) uintptr {
+	var pcs [maxDepth]uintptr
+
+	// skip runtime.Callers and PC
+	n := runtime.Callers(skip+2, pcs[:])
+
+	for _, pc := range pcs[:n] {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if !hasAnyPrefix(frame.Function, prefixes) {
+			return pc
+		}
+	}
+
+	return 0
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
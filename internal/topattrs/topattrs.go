@@ -0,0 +1,95 @@
+// Package topattrs lets handler middlewares add attributes to the top level of
+// records even after WithGroup, where attributes added to a record would end up
+// inside the open groups.
+package topattrs
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Chain records the groups and attributes added to a handler since its first group.
+// The zero Chain describes a handler without groups.
+type Chain struct {
+	root  slog.Handler             // handler before the first group, nil without groups
+	ops   []op                     // groups and attributes added to root, in order
+	cache *atomic.Pointer[handler] // handler built by the last CachedHandler call
+}
+
+// handler is a rebuilt handler with the key of its attributes
+type handler struct {
+	key any
+	h   slog.Handler
+}
+
+// op is a single WithGroup or WithAttrs call
+type op struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Grouped reports whether the handler has groups
+func (c Chain) Grouped() bool {
+	return c.root != nil
+}
+
+// WithAttrs records a WithAttrs call
+// attrs: Attributes passed to WithAttrs
+// Returns: Chain of the derived handler
+func (c Chain) WithAttrs(attrs []slog.Attr) Chain {
+	if c.root == nil {
+		return c
+	}
+
+	return Chain{root: c.root, ops: append(c.ops[:len(c.ops):len(c.ops)], op{attrs: attrs}), cache: new(atomic.Pointer[handler])}
+}
+
+// WithGroup records a WithGroup call
+// h: Handler WithGroup is called on
+// name: Group name, which must not be empty
+// Returns: Chain of the derived handler
+func (c Chain) WithGroup(h slog.Handler, name string) Chain {
+	root := c.root
+	if root == nil {
+		root = h
+	}
+
+	return Chain{root: root, ops: append(c.ops[:len(c.ops):len(c.ops)], op{group: name}), cache: new(atomic.Pointer[handler])}
+}
+
+// Handler rebuilds the grouped handler with attributes added before its first group.
+// It must only be called when the chain is grouped.
+// attrs: Top-level attributes
+// Returns: Handler writing attrs at the top level of its records
+func (c Chain) Handler(attrs []slog.Attr) slog.Handler {
+	h := c.root.WithAttrs(attrs)
+
+	for _, o := range c.ops {
+		if o.group != "" {
+			h = h.WithGroup(o.group)
+		} else {
+			h = h.WithAttrs(o.attrs)
+		}
+	}
+
+	return h
+}
+
+// CachedHandler works like Handler but reuses the handler built by the previous call
+// with the same key. Rebuilding calls WithAttrs and WithGroup on every handler of the
+// chain, so records sharing their top-level attributes, such as the records of a
+// request, are only charged for it once; records alternating between keys still are.
+// It must only be called when the chain is grouped.
+// key: Comparable value identifying the attributes
+// attrs: Top-level attributes, called when the handler is rebuilt
+// Returns: Handler writing the attributes at the top level of its records
+func (c Chain) CachedHandler(key any, attrs func() []slog.Attr) slog.Handler {
+	if last := c.cache.Load(); last != nil && last.key == key {
+		return last.h
+	}
+
+	h := c.Handler(attrs())
+	c.cache.Store(&handler{key: key, h: h})
+
+	return h
+}
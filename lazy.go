@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LazyValue is a function that produces an attribute value on demand.
+// It implements [slog.LogValuer], so the function is called only when
+// a handler actually processes the record containing it.
+type LazyValue func() any
+
+// LogValue implements [slog.LogValuer]
+func (f LazyValue) LogValue() slog.Value {
+	if f == nil {
+		return slog.StringValue("nil")
+	}
+
+	return slog.AnyValue(f())
+}
+
+// Lazy creates an attribute whose value is computed only when the record is handled.
+// Use it for values that are expensive to build, such as serialized payloads or diffs.
+// key: The attribute key
+// fn: Function producing the value. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func Lazy(key string, fn func() any) slog.Attr {
+	return slog.Any(key, LazyValue(fn))
+}
+
+// DebugFunc logs at [LevelDebug] with arguments built by fn.
+// fn is not called when the debug level is disabled.
+func (l *Logger) DebugFunc(msg string, fn func() []any) {
+	l.logFunc(nil, LevelDebug, msg, fn)
+}
+
+// InfoFunc logs at [LevelInfo] with arguments built by fn.
+// fn is not called when the info level is disabled.
+func (l *Logger) InfoFunc(msg string, fn func() []any) {
+	l.logFunc(nil, LevelInfo, msg, fn)
+}
+
+// WarnFunc logs at [LevelWarn] with arguments built by fn.
+// fn is not called when the warn level is disabled.
+func (l *Logger) WarnFunc(msg string, fn func() []any) {
+	l.logFunc(nil, LevelWarn, msg, fn)
+}
+
+// ErrorFunc logs at [LevelError] with arguments built by fn.
+// fn is not called when the error level is disabled.
+func (l *Logger) ErrorFunc(msg string, fn func() []any) {
+	l.logFunc(nil, LevelError, msg, fn)
+}
+
+// LogFunc logs at the given level with the given context and arguments built by fn.
+// fn is not called when the level is disabled.
+func (l *Logger) LogFunc(ctx context.Context, level Level, msg string, fn func() []any) {
+	l.logFunc(ctx, level, msg, fn)
+}
+
+// logFunc checks the level before building the arguments.
+// It must be called directly from an exported method to keep source locations correct.
+func (l *Logger) logFunc(ctx context.Context, level Level, msg string, fn func() []any) {
+	checkCtx := ctx
+	if checkCtx == nil {
+		checkCtx = context.Background()
+	}
+
+	if !l.Enabled(checkCtx, level) {
+		return
+	}
+
+	var args []any
+	if fn != nil {
+		args = fn()
+	}
+
+	logWithSkip(ctx, l.Logger, 4, level, msg, args...)
+}
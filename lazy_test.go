@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     Level
+		wantCalls int
+		wantOut   string
+	}{
+		{"enabled level", LevelInfo, 1, "key=value"},
+		{"disabled level", LevelDebug, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelInfo}))
+
+			calls := 0
+			logger.Log(context.Background(), tt.level, "msg", Lazy("key", func() any {
+				calls++
+
+				return "value"
+			}))
+
+			if calls != tt.wantCalls {
+				t.Errorf("Lazy() calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if !strings.Contains(buf.String(), tt.wantOut) {
+				t.Errorf("Lazy() output = %q, want it to contain %q", buf.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestLazyNil(t *testing.T) {
+	got := Lazy("key", nil).Value.Resolve()
+	if got.String() != "nil" {
+		t.Errorf("Lazy(nil) = %v, want nil", got)
+	}
+}
+
+func TestLoggerFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		log       func(l *Logger, fn func() []any)
+		wantCalls int
+	}{
+		{"debug disabled", func(l *Logger, fn func() []any) { l.DebugFunc("msg", fn) }, 0},
+		{"info enabled", func(l *Logger, fn func() []any) { l.InfoFunc("msg", fn) }, 1},
+		{"warn enabled", func(l *Logger, fn func() []any) { l.WarnFunc("msg", fn) }, 1},
+		{"error enabled", func(l *Logger, fn func() []any) { l.ErrorFunc("msg", fn) }, 1},
+		{"log func disabled", func(l *Logger, fn func() []any) { l.LogFunc(context.Background(), LevelDebug, "msg", fn) }, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := &Logger{slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level:     LevelInfo,
+				AddSource: true,
+			}))}
+
+			calls := 0
+			tt.log(logger, func() []any {
+				calls++
+
+				return []any{"key", "value"}
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if tt.wantCalls > 0 {
+				if !strings.Contains(buf.String(), "key=value") {
+					t.Errorf("output = %q, want key=value", buf.String())
+				}
+
+				if !strings.Contains(buf.String(), "lazy_test.go") {
+					t.Errorf("output = %q, want source pointing to lazy_test.go", buf.String())
+				}
+			}
+		})
+	}
+}
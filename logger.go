@@ -7,6 +7,7 @@ package logging
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"os"
@@ -23,6 +24,9 @@ const (
 	defaultAddShortSource = false
 	defaultJSONFormat     = false
 	defaultSetDefault     = false
+
+	// fatalLevelName is the rendered name of LevelFatal
+	fatalLevelName = "FATAL"
 )
 
 var (
@@ -41,19 +45,37 @@ type Options struct {
 	AddSource      bool              // Whether to add source file location
 	AddShortSource bool              // Whether to shorten source file paths
 	JSONFormat     bool              // Use JSON format instead of text
+	Writer         io.Writer         // Destination of the records, os.Stdout by default
 	SetDefault     bool              // Set this logger as the default
 	ReplaceAttrs   map[string]string // Attribute key replacements
+	RedactKeys     []string          // Key names or glob patterns whose values are redacted
+	RedactAction   RedactAction      // How redacted values are rendered
+	RedactMask     string            // Replacement used by RedactActionMask
+	RedactHashKey  []byte            // HMAC key used by RedactActionHash, random per process when empty
+	ScrubDetectors ScrubDetector     // PII detectors applied to string values and messages
+	Middlewares    []Middleware      // Handler middlewares, the first one sees records first
+
+	redactor *redactor
+	scrubber *scrubber
 }
 
 // Option defines a function type for configuring Options
 type Option func(*Options)
 
+// Middleware wraps a handler to enrich, filter or redirect records
+type Middleware func(slog.Handler) slog.Handler
+
 // shortSourceAttr shortens the source file path in the attribute
 // a: Original source attribute
 // newKey: New key name for the attribute
 // Returns: Modified attribute with shortened source path
 func (o *Options) shortSourceAttr(a slog.Attr, newKey string) slog.Attr {
 	if src, ok := a.Value.Any().(*slog.Source); ok {
+		if src.File == "" {
+			// records without a program counter have no source
+			return slog.Attr{}
+		}
+
 		dir, file := filepath.Split(src.File)
 
 		dirParts := strings.Split(filepath.ToSlash(filepath.Clean(dir)), "/")
@@ -84,11 +106,17 @@ func (o *Options) shortSourceAttr(a slog.Attr, newKey string) slog.Attr {
 // a: Original attribute
 // Returns: Modified attribute
 func (o *Options) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	a = o.sanitizeAttr(groups, a)
+
+	if len(groups) != 0 {
+		return a
+	}
+
 	if newKey, ok := o.ReplaceAttrs[a.Key]; ok {
 		if a.Key == slog.SourceKey && o.AddShortSource {
 			return o.shortSourceAttr(a, newKey)
 		} else if a.Key == slog.LevelKey {
-			return o.replaceLevel(a, "FATAL")
+			return o.replaceLevel(a, newKey)
 		}
 
 		return slog.Attr{
@@ -100,47 +128,81 @@ func (o *Options) replaceAttr(groups []string, a slog.Attr) slog.Attr {
 	return a
 }
 
-func (o *Options) replaceLevel(a slog.Attr, levelName string) slog.Attr {
-	if lvl, ok := a.Value.Any().(slog.Level); ok {
-		if lvl == LevelFatal {
-			return slog.Attr{
-				Key:   slog.LevelKey,
-				Value: slog.StringValue(levelName),
-			}
-		}
+// sanitizeAttr redacts sensitive keys and scrubs PII from values.
+// Handlers only pass the leaves of groups, so a leaf is redacted when the key
+// of any enclosing group matches, such as the fields of a "password" group.
+// groups: Current attribute groups
+// a: Original attribute
+// Returns: Redacted or scrubbed attribute
+func (o *Options) sanitizeAttr(groups []string, a slog.Attr) slog.Attr {
+	if o.redactor.match(a.Key) && !isBuiltinKey(groups, a.Key) || o.redactor.matchAny(groups) {
+		return o.redactAttr(a)
 	}
 
-	return a
+	return o.scrubAttr(a)
 }
 
+// replaceLevel renames the level attribute and names [LevelFatal] "FATAL"
+// a: Original level attribute
+// newKey: New key name for the attribute
+// Returns: Modified level attribute
+func (o *Options) replaceLevel(a slog.Attr, newKey string) slog.Attr {
+	if lvl, ok := a.Value.Any().(slog.Level); ok && lvl == LevelFatal {
+		return slog.String(newKey, fatalLevelName)
+	}
+
+	return slog.Attr{
+		Key:   newKey,
+		Value: a.Value,
+	}
+}
+
+// Logger wraps slog.Logger with Fatal methods and named sub-loggers
 type Logger struct {
 	*slog.Logger
 }
 
-// NewLogger creates a new configured logger instance
+// newOptions applies the options on top of the default configuration
 // opts: Variadic list of configuration options
-// Returns: Configured slog.Logger instance
-func NewLogger(opts ...Option) *Logger {
+// Returns: Resulting configuration
+func newOptions(opts ...Option) *Options {
 	config := &Options{
 		LogLevel:       defaultLogLevel,
 		AddSource:      defaultAddSource,
 		AddShortSource: defaultAddShortSource,
 		JSONFormat:     defaultJSONFormat,
+		Writer:         os.Stdout,
 		SetDefault:     defaultSetDefault,
 		ReplaceAttrs:   maps.Clone(defaultReplaceAttrs),
+		RedactKeys:     DefaultRedactKeys(),
+		RedactAction:   defaultRedactAction,
+		RedactMask:     defaultRedactMask,
+		ScrubDetectors: defaultScrubDetectors,
 	}
 
 	for _, opt := range opts {
 		opt(config)
 	}
 
+	config.redactor = newRedactor(config.RedactKeys)
+	config.scrubber = newScrubber(config.ScrubDetectors)
+
+	return config
+}
+
+// NewLogger creates a new configured logger instance
+// opts: Variadic list of configuration options
+// Returns: Configured slog.Logger instance
+func NewLogger(opts ...Option) *Logger {
+	config := newOptions(opts...)
+
 	handlerOpts := &slog.HandlerOptions{
 		Level:       config.LogLevel,
 		AddSource:   config.AddSource || config.AddShortSource,
 		ReplaceAttr: config.replaceAttr,
 	}
 
-	w := os.Stdout
+	w := config.Writer
 
 	var handler slog.Handler
 
@@ -150,6 +212,12 @@ func NewLogger(opts ...Option) *Logger {
 		handler = slog.NewTextHandler(w, handlerOpts)
 	}
 
+	handler = NewContextHandler(handler)
+
+	for i := len(config.Middlewares) - 1; i >= 0; i-- {
+		handler = config.Middlewares[i](handler)
+	}
+
 	logger := slog.New(handler)
 
 	if config.SetDefault {
@@ -161,6 +229,14 @@ func NewLogger(opts ...Option) *Logger {
 	}
 }
 
+// Sanitizer returns the key redaction and PII scrubbing of a logger created with the
+// options, for handlers exporting records outside of it, such as span events
+// opts: Variadic list of configuration options; only redaction and scrubbing options apply
+// Returns: Function with the signature of slog.HandlerOptions.ReplaceAttr
+func Sanitizer(opts ...Option) func(groups []string, a slog.Attr) slog.Attr {
+	return newOptions(opts...).sanitizeAttr
+}
+
 // Fatal logs at [LevelFatal]
 func (l *Logger) Fatal(msg string, args ...any) {
 	logWithSkip(nil, l.Logger, 3, LevelFatal, msg, args...)
@@ -179,6 +255,58 @@ func (l *Logger) L() *slog.Logger {
 	return l.Logger
 }
 
+// Named returns a sub-logger whose records carry the [NameKey] attribute.
+// Names of nested sub-loggers are joined with dots, for example "api.users".
+// The name is kept by With, but not by WithGroup, after which it would no longer
+// be at the top level.
+// name: Name of the sub-logger
+// Returns: Named logger
+func (l *Logger) Named(name string) *Logger {
+	base := l.Handler()
+
+	if h, ok := base.(*namedHandler); ok {
+		base = h.base
+		name = h.name + "." + name
+	}
+
+	return &Logger{slog.New(&namedHandler{
+		Handler: base.WithAttrs([]slog.Attr{slog.String(NameKey, name)}),
+		base:    base,
+		name:    name,
+	})}
+}
+
+// Name returns the name set by [Logger.Named] or an empty string
+func (l *Logger) Name() string {
+	if h, ok := l.Handler().(*namedHandler); ok {
+		return h.name
+	}
+
+	return ""
+}
+
+// namedHandler carries the name of a logger set by Named in its handler,
+// so that renaming replaces the name attribute instead of adding another one
+type namedHandler struct {
+	slog.Handler              // handler with the name attribute
+	base         slog.Handler // handler without the name attribute
+	name         string       // dot-separated name
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *namedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &namedHandler{
+		Handler: h.Handler.WithAttrs(attrs),
+		base:    h.base.WithAttrs(attrs),
+		name:    h.name,
+	}
+}
+
+// WithGroup implements [slog.Handler]
+func (h *namedHandler) WithGroup(name string) slog.Handler {
+	return h.Handler.WithGroup(name)
+}
+
 func logWithSkip(ctx context.Context, l *slog.Logger, skip int, level Level, msg string, args ...any) {
 	var pcs [1]uintptr
 
@@ -230,6 +358,15 @@ func WithJSONFormat(format bool) Option {
 	}
 }
 
+// WithWriter sets the destination of the records
+// w: Writer receiving formatted records, for example a file or a test buffer
+// Returns: Configuration option function
+func WithWriter(w io.Writer) Option {
+	return func(o *Options) {
+		o.Writer = w
+	}
+}
+
 // WithSetDefault sets whether to make this logger default
 // setDefault: Whether to set as default logger
 // Returns: Configuration option function
@@ -250,3 +387,13 @@ func WithReplaceDefaultKeyName(keyName, replaceKeyName string) Option {
 		}
 	}
 }
+
+// WithMiddleware adds handler middlewares such as trace correlation or sampling.
+// Middlewares run in the given order before records reach the output handler.
+// mw: Middlewares to add
+// Returns: Configuration option function
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, mw...)
+	}
+}
@@ -0,0 +1,388 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenTime is the time of every record in golden files
+var goldenTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// sourceLine matches short source locations, whose directory depends on the checkout
+var sourceLine = regexp.MustCompile(`[\w.-]+/logger_test\.go:\d+`)
+
+func TestNewLoggerGolden(t *testing.T) {
+	logAll := func(l *Logger) {
+		l.Debug("debug message", "n", 1)
+		l.Info("user created", String("user", "alice"), Int("age", 30), Dict("http", String("method", "GET"), Int("level", 2)))
+		l.With("svc", "api").Warn("slow request", Duration("elapsed", 1500*time.Millisecond))
+		l.Error("request failed", Err(errors.New("connection refused")))
+		l.Log(context.Background(), LevelFatal, "fatal message")
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(l *Logger)
+	}{
+		{"text", nil, logAll},
+		{"json", []Option{WithJSONFormat(true)}, logAll},
+		{"text_info_level", []Option{WithLogLevel(LevelInfo)}, logAll},
+		{"text_short_source", []Option{WithShortSource(true)}, func(l *Logger) { l.Info("with source") }},
+		{"json_short_source", []Option{WithJSONFormat(true), WithShortSource(true)}, func(l *Logger) { l.Info("with source") }},
+		{
+			"text_renamed_keys",
+			[]Option{
+				WithReplaceDefaultKeyName(MessageKey, "message"),
+				WithReplaceDefaultKeyName(LevelKey, "severity"),
+				WithReplaceDefaultKeyName(TimeKey, "ts"),
+				WithReplaceDefaultKeyName(SourceKey, "caller"),
+				WithShortSource(true),
+			},
+			logAll,
+		},
+		{
+			"json_redact_scrub",
+			[]Option{WithJSONFormat(true), WithScrub(ScrubEmail | ScrubCreditCard)},
+			func(l *Logger) {
+				l.Info("login", "password", "hunter2", "email", "alice@example.com", Dict("payment", String("card", "4111 1111 1111 1111")))
+				l.Info("token refreshed", "access_token", "abc", "user", "bob@example.com")
+			},
+		},
+		{
+			"text_context",
+			nil,
+			func(l *Logger) {
+				ctx := ContextWithAttrs(context.Background(), String("request_id", "r1"), String("tenant", "acme"))
+				l.InfoContext(ctx, "with context", "k", "v")
+				l.With("tenant", "logger").InfoContext(ctx, "logger attr wins")
+				l.Named("api").Named("users").InfoContext(ctx, "named")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := append([]Option{WithWriter(&buf), WithMiddleware(fixedTime(goldenTime))}, tt.opts...)
+			tt.log(NewLogger(opts...))
+
+			got := sourceLine.ReplaceAll(buf.Bytes(), []byte("logging/logger_test.go:0"))
+			golden := filepath.Join("testdata", tt.name+".golden")
+
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("output mismatch with %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestReplaceAttr(t *testing.T) {
+	source := &slog.Source{Function: "main.run", File: "/home/user/app/cmd/main.go", Line: 42}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		groups []string
+		attr   slog.Attr
+		want   string
+	}{
+		{"unchanged", nil, nil, String("k", "v"), "k=v"},
+		{"level", nil, nil, slog.Any(LevelKey, LevelWarn), "level=WARN"},
+		{"fatal level", nil, nil, slog.Any(LevelKey, LevelFatal), "level=FATAL"},
+		{"renamed level", []Option{WithReplaceDefaultKeyName(LevelKey, "severity")}, nil, slog.Any(LevelKey, LevelInfo), "severity=INFO"},
+		{
+			"renamed fatal level",
+			[]Option{WithReplaceDefaultKeyName(LevelKey, "severity")},
+			nil,
+			slog.Any(LevelKey, LevelFatal),
+			"severity=FATAL",
+		},
+		{"renamed message", []Option{WithReplaceDefaultKeyName(MessageKey, "message")}, nil, String(MessageKey, "m"), "message=m"},
+		{"unknown key not renamed", []Option{WithReplaceDefaultKeyName("k", "key")}, nil, String("k", "v"), "k=v"},
+		{
+			"grouped key not renamed",
+			[]Option{WithReplaceDefaultKeyName(LevelKey, "severity")},
+			[]string{"http"},
+			Int(LevelKey, 2),
+			"level=2",
+		},
+		{"short source", []Option{WithShortSource(true)}, nil, slog.Any(SourceKey, source), "source=cmd/main.go:42"},
+		{
+			"renamed short source",
+			[]Option{WithShortSource(true), WithReplaceDefaultKeyName(SourceKey, "caller")},
+			nil,
+			slog.Any(SourceKey, source),
+			"caller=cmd/main.go:42",
+		},
+		{"empty short source", []Option{WithShortSource(true)}, nil, slog.Any(SourceKey, &slog.Source{}), ""},
+		{"redacted", nil, nil, String("password", "p"), "password=[REDACTED]"},
+		{"redacted in group", nil, []string{"db"}, String("password", "p"), "password=[REDACTED]"},
+		{"scrubbed", []Option{WithScrub(ScrubEmail)}, nil, String("user", "alice@example.com"), "user=a****@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newOptions(tt.opts...).replaceAttr(tt.groups, tt.attr)
+
+			if tt.want == "" {
+				if !got.Equal(slog.Attr{}) {
+					t.Errorf("replaceAttr() = %v, want empty attribute", got)
+				}
+
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("replaceAttr() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLoggerReplaceAttrs(t *testing.T) {
+	renamed := []Option{
+		WithReplaceDefaultKeyName(LevelKey, "severity"),
+		WithReplaceDefaultKeyName(MessageKey, "message"),
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(l *Logger)
+		want string
+	}{
+		{
+			"user keys inside groups are not renamed",
+			renamed,
+			func(l *Logger) { l.WithGroup("job").Info("run", "level", 3, "msg", "m") },
+			`severity=INFO message=run job.level=3 job.msg=m`,
+		},
+		{
+			"level key renamed for every level",
+			renamed,
+			func(l *Logger) {
+				l.Warn("w")
+				l.Log(context.Background(), LevelFatal, "f")
+			},
+			"severity=WARN message=w\nseverity=FATAL message=f",
+		},
+		{
+			"source omitted for records without a program counter",
+			[]Option{WithShortSource(true)},
+			func(l *Logger) {
+				_ = l.Handler().Handle(context.Background(), slog.NewRecord(time.Now(), LevelInfo, "no pc", 0))
+			},
+			`level=INFO msg="no pc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := append([]Option{WithWriter(&buf)}, tt.opts...)
+			tt.log(NewLogger(opts...))
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			for i, line := range lines {
+				_, lines[i], _ = strings.Cut(line, " ") // drop the time
+			}
+
+			if got := strings.Join(lines, "\n"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortSourceAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"nested", "/home/user/app/internal/db/conn.go", "db/conn.go:7"},
+		{"root", "/main.go", "main.go:7"},
+		{"relative", "app/main.go", "app/main.go:7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newOptions().shortSourceAttr(slog.Any(SourceKey, &slog.Source{File: tt.file, Line: 7}), "src")
+
+			if got.Key != "src" || got.Value.String() != tt.want {
+				t.Errorf("shortSourceAttr() = %v, want src=%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFatal(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{"fatal", func(l *Logger) { l.Fatal("fatal message", "k", "v") }},
+		{"fatal context", func(l *Logger) { l.FatalContext(context.Background(), "fatal message", "k", "v") }},
+		{
+			"package fatal",
+			func(l *Logger) {
+				slog.SetDefault(l.Logger)
+				Fatal("fatal message", "k", "v")
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Fatal exits the process, so it runs in a child test process
+			if os.Getenv("LOGGING_TEST_FATAL") == tt.name {
+				tt.log(NewLogger(WithShortSource(true)))
+
+				return
+			}
+
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatal$/^"+strings.ReplaceAll(tests[i].name, " ", "_")+"$")
+			cmd.Env = append(os.Environ(), "LOGGING_TEST_FATAL="+tt.name)
+
+			out, err := cmd.Output()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				t.Fatalf("process error = %v, want exit status 1", err)
+			}
+
+			for _, want := range []string{"level=FATAL", `msg="fatal message"`, "k=v", "logger_test.go:"} {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("output = %q, want it to contain %s", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	logger := NewLogger(WithWriter(&bytes.Buffer{}))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *slog.Logger
+	}{
+		{"stored", ContextWithLogger(context.Background(), logger), logger.Logger},
+		{"default", context.Background(), slog.Default()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoggerFromContext(tt.ctx).Logger; got != tt.want {
+				t.Errorf("LoggerFromContext() = %p, want %p", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerNamed(t *testing.T) {
+	tests := []struct {
+		name     string
+		logger   func(l *Logger) *Logger
+		wantName string
+		wantOut  string
+	}{
+		{"unnamed", func(l *Logger) *Logger { return l }, "", "msg=msg"},
+		{"named", func(l *Logger) *Logger { return l.Named("api") }, "api", "msg=msg logger=api"},
+		{"nested", func(l *Logger) *Logger { return l.Named("api").Named("users") }, "api.users", "msg=msg logger=api.users"},
+		{
+			"kept by With",
+			func(l *Logger) *Logger { return &Logger{l.Named("api").With("k", "v")} },
+			"api",
+			"msg=msg logger=api k=v",
+		},
+		{
+			"renamed after With",
+			func(l *Logger) *Logger { return (&Logger{l.Named("api").With("k", "v")}).Named("users") },
+			"api.users",
+			"msg=msg k=v logger=api.users",
+		},
+		{
+			"dropped by WithGroup",
+			func(l *Logger) *Logger { return (&Logger{l.Named("api").WithGroup("g")}).Named("users") },
+			"users",
+			"msg=msg logger=api g.logger=users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := tt.logger(&Logger{Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: dropTimeAndLevel,
+			}))})
+			logger.Info("msg")
+
+			if got := logger.Name(); got != tt.wantName {
+				t.Errorf("Name() = %q, want %q", got, tt.wantName)
+			}
+
+			if got := strings.TrimSpace(buf.String()); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+// fixedTime returns a middleware setting the time of records, for golden files
+func fixedTime(tm time.Time) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return &fixedTimeHandler{next: next, time: tm}
+	}
+}
+
+type fixedTimeHandler struct {
+	next slog.Handler
+	time time.Time
+}
+
+func (h *fixedTimeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *fixedTimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = h.time
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *fixedTimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fixedTimeHandler{next: h.next.WithAttrs(attrs), time: h.time}
+}
+
+func (h *fixedTimeHandler) WithGroup(name string) slog.Handler {
+	return &fixedTimeHandler{next: h.next.WithGroup(name), time: h.time}
+}
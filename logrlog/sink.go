@@ -0,0 +1,164 @@
+// Package logrlog adapts the logging package to go-logr, the logging API used by
+// client-go, controller-runtime and other Kubernetes-style libraries.
+//
+//	ctrl.SetLogger(logrlog.New(logger))
+//
+// V-levels map to levels below Info (V(0) is Info, V(1) is Debug+3, V(4) is Debug),
+// names map to [logging.Logger.Named] and WithValues to attributes. [FromLogr]
+// covers the reverse direction.
+package logrlog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// logrSkip is the number of frames between runtime.Callers and the logr.Logger method
+const logrSkip = 3
+
+// LogSink is a [logr.LogSink] writing into a [logging.Logger]
+type LogSink struct {
+	named     *logging.Logger                   // logger carrying the name, without values
+	logger    *slog.Logger                      // named logger with values and groups applied
+	with      func(l *slog.Logger) *slog.Logger // values and groups replayed when the name changes
+	callDepth int
+}
+
+var (
+	_ logr.LogSink          = (*LogSink)(nil)
+	_ logr.CallDepthLogSink = (*LogSink)(nil)
+	_ logr.SlogSink         = (*LogSink)(nil)
+)
+
+// NewLogSink creates a LogSink writing into the logger
+// logger: Logger receiving the records
+// Returns: LogSink for logr.New
+func NewLogSink(logger *logging.Logger) *LogSink {
+	return &LogSink{
+		named:  logger,
+		logger: logger.Logger,
+		with:   func(l *slog.Logger) *slog.Logger { return l },
+	}
+}
+
+// New returns a logr.Logger writing into the logger
+// logger: Logger receiving the records
+// Returns: logr.Logger
+func New(logger *logging.Logger) logr.Logger {
+	return logr.New(NewLogSink(logger))
+}
+
+// FromLogr returns a Logger writing into a logr.Logger.
+// Levels at or above Info are logged with V(0), lower levels with V(Info-level);
+// Error and above go through logr's Error method.
+// l: logr.Logger receiving the records
+// Returns: Logger
+func FromLogr(l logr.Logger) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(logr.ToSlogHandler(l))}
+}
+
+// VLevel maps a logr verbosity to a level: V(0) is Info and each step goes one level lower
+// v: logr verbosity
+// Returns: Level
+func VLevel(v int) logging.Level {
+	return logging.LevelInfo - slog.Level(v)
+}
+
+// Init implements [logr.LogSink]
+func (s *LogSink) Init(info logr.RuntimeInfo) {
+	s.callDepth += info.CallDepth
+}
+
+// Enabled implements [logr.LogSink]
+func (s *LogSink) Enabled(v int) bool {
+	return s.logger.Enabled(context.Background(), VLevel(v))
+}
+
+// Info implements [logr.LogSink]
+func (s *LogSink) Info(v int, msg string, keysAndValues ...any) {
+	s.log(VLevel(v), msg, keysAndValues)
+}
+
+// Error implements [logr.LogSink]
+func (s *LogSink) Error(err error, msg string, keysAndValues ...any) {
+	s.log(logging.LevelError, msg, keysAndValues, logging.Err(err))
+}
+
+// log writes a record whose source points to the caller of the logr.Logger method
+func (s *LogSink) log(level logging.Level, msg string, keysAndValues []any, attrs ...slog.Attr) {
+	ctx := context.Background()
+	if !s.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+
+	// skip runtime.Callers, log, Info or Error, and the logr frames reported by Init
+	runtime.Callers(logrSkip+s.callDepth, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(keysAndValues...)
+	r.AddAttrs(attrs...)
+
+	_ = s.logger.Handler().Handle(ctx, r)
+}
+
+// WithValues implements [logr.LogSink]
+func (s *LogSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return s.withFunc(func(l *slog.Logger) *slog.Logger { return l.With(keysAndValues...) })
+}
+
+// WithName implements [logr.LogSink] using [logging.Logger.Named]
+func (s *LogSink) WithName(name string) logr.LogSink {
+	named := s.named.Named(name)
+
+	return &LogSink{
+		named:     named,
+		logger:    s.with(named.Logger),
+		with:      s.with,
+		callDepth: s.callDepth,
+	}
+}
+
+// WithCallDepth implements [logr.CallDepthLogSink]
+func (s *LogSink) WithCallDepth(depth int) logr.LogSink {
+	clone := *s
+	clone.callDepth += depth
+
+	return &clone
+}
+
+// Handle implements [logr.SlogSink] so that logr.ToSlogHandler passes records through
+func (s *LogSink) Handle(ctx context.Context, r slog.Record) error {
+	return s.logger.Handler().Handle(ctx, r)
+}
+
+// WithAttrs implements [logr.SlogSink]
+func (s *LogSink) WithAttrs(attrs []slog.Attr) logr.SlogSink {
+	return s.withFunc(func(l *slog.Logger) *slog.Logger {
+		return slog.New(l.Handler().WithAttrs(attrs))
+	})
+}
+
+// WithGroup implements [logr.SlogSink]
+func (s *LogSink) WithGroup(name string) logr.SlogSink {
+	return s.withFunc(func(l *slog.Logger) *slog.Logger { return l.WithGroup(name) })
+}
+
+// withFunc returns a copy of the sink with fn applied now and after later name changes
+func (s *LogSink) withFunc(fn func(l *slog.Logger) *slog.Logger) *LogSink {
+	prev := s.with
+
+	return &LogSink{
+		named:     s.named,
+		logger:    fn(s.logger),
+		with:      func(l *slog.Logger) *slog.Logger { return fn(prev(l)) },
+		callDepth: s.callDepth,
+	}
+}
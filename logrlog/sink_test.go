@@ -0,0 +1,215 @@
+package logrlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestLogSink(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l logr.Logger)
+		want string
+	}{
+		{"info", func(l logr.Logger) { l.Info("msg", "k", "v") }, "level=INFO msg=msg k=v"},
+		{"verbosity 1", func(l logr.Logger) { l.V(1).Info("msg") }, "level=DEBUG+3 msg=msg"},
+		{"verbosity 4", func(l logr.Logger) { l.V(4).Info("msg") }, "level=DEBUG msg=msg"},
+		{"verbosity disabled", func(l logr.Logger) { l.V(5).Info("msg") }, ""},
+		{
+			"error",
+			func(l logr.Logger) { l.V(5).Error(errors.New("boom"), "failed", "k", "v") },
+			"level=ERROR msg=failed k=v error=boom",
+		},
+		{"name", func(l logr.Logger) { l.WithName("api").WithName("users").Info("msg") }, "level=INFO msg=msg logger=api.users"},
+		{"values", func(l logr.Logger) { l.WithValues("k", "v").Info("msg", "n", 1) }, "level=INFO msg=msg k=v n=1"},
+		{
+			"values before name",
+			func(l logr.Logger) { l.WithValues("k", "v").WithName("api").Info("msg") },
+			"level=INFO msg=msg logger=api k=v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(New(newTestLogger(&buf, false)))
+
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSinkEnabled(t *testing.T) {
+	l := New(newTestLogger(&bytes.Buffer{}, false))
+
+	for v, want := range []bool{true, true, true, true, true, false} {
+		if got := l.V(v).Enabled(); got != want {
+			t.Errorf("V(%d).Enabled() = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestLogSinkSource(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l logr.Logger)
+	}{
+		{"info", func(l logr.Logger) { l.Info("msg") }},
+		{"error", func(l logr.Logger) { l.Error(nil, "msg") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(New(newTestLogger(&buf, true)))
+
+			if !strings.Contains(buf.String(), "sink_test.go") {
+				t.Errorf("output = %q, want source pointing to sink_test.go", buf.String())
+			}
+		})
+	}
+}
+
+func TestFromLogr(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *logging.Logger)
+		want string
+	}{
+		{"info", func(l *logging.Logger) { l.Info("msg", "k", "v") }, `"level":0,"msg":"msg","k":"v"`},
+		{"debug", func(l *logging.Logger) { l.Debug("msg") }, `"level":4,"msg":"msg"`},
+		{"error", func(l *logging.Logger) { l.Error("msg", logging.Err(errors.New("boom"))) }, `"error":"boom"`},
+		{"name", func(l *logging.Logger) { l.Named("api").Info("msg") }, `"level":0,"msg":"msg","logger":"api"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(FromLogr(funcr.NewJSON(func(obj string) { buf.WriteString(obj) }, funcr.Options{Verbosity: 10})))
+
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestSlogConformance runs the slog handler conformance tests in both directions,
+// the same way go-logr tests its own slog integration
+func TestSlogConformance(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(buf *bytes.Buffer) slog.Handler
+		exceptions []string
+	}{
+		{
+			"logging to logr",
+			func(buf *bytes.Buffer) slog.Handler {
+				return logr.ToSlogHandler(New(&logging.Logger{Logger: slog.New(slog.NewJSONHandler(buf, nil))}))
+			},
+			nil,
+		},
+		{
+			"logr to logging",
+			func(buf *bytes.Buffer) slog.Handler {
+				sink := funcr.NewJSON(func(obj string) { fmt.Fprintln(buf, obj) }, funcr.Options{
+					LogTimestamp:       true,
+					Verbosity:          10,
+					RenderBuiltinsHook: renameTimestamp,
+				})
+
+				return FromLogr(sink).Handler()
+			},
+			// funcr stamps records itself
+			[]string{"a Handler should ignore a zero Record.Time"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := slogtest.TestHandler(tt.handler(&buf), func() []map[string]any {
+				var records []map[string]any
+
+				for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+					if len(line) == 0 {
+						continue
+					}
+
+					var m map[string]any
+					if err := json.Unmarshal(line, &m); err != nil {
+						t.Fatalf("decode %q: %v", line, err)
+					}
+
+					records = append(records, m)
+				}
+
+				return records
+			})
+
+			var joined interface{ Unwrap() []error }
+			if errors.As(err, &joined) {
+				for _, err := range joined.Unwrap() {
+					if !containsAny(err.Error(), tt.exceptions) {
+						t.Errorf("conformance: %v", err)
+					}
+				}
+			} else if err != nil {
+				t.Errorf("conformance: %v", err)
+			}
+		})
+	}
+}
+
+// newTestLogger returns a Debug text logger without time for deterministic output
+func newTestLogger(buf *bytes.Buffer, source bool) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:     logging.LevelDebug,
+		AddSource: source,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))}
+}
+
+// renameTimestamp maps funcr's "ts" key to the key expected by slogtest
+func renameTimestamp(kvs []any) []any {
+	for i := 0; i < len(kvs); i += 2 {
+		if kvs[i] == "ts" {
+			kvs[i] = slog.TimeKey
+		}
+	}
+
+	return kvs
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+// Package logruslog lets logrus call sites log through a logging.Logger, so that code
+// migrating from github.com/sirupsen/logrus shares the output format and sinks of NewLogger.
+//
+//	var log logrus.FieldLogger = logruslog.New(logger)
+//	log.WithFields(logrus.Fields{"user": name}).Info("user created")
+//
+// Entry fields become attributes sorted by key, the entry context is passed to the
+// handler and Trace maps to Debug-4. Panic and Fatal map to [logging.LevelFatal];
+// logrus still panics or exits after writing the record.
+package logruslog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sergei-galichev/logging"
+	"github.com/sergei-galichev/logging/internal/caller"
+)
+
+// logrusPrefix matches the functions of logrus skipped when looking for the caller
+const logrusPrefix = "github.com/sirupsen/logrus."
+
+// levels lists logrus levels from the most to the least verbose
+var levels = []logrus.Level{
+	logrus.TraceLevel,
+	logrus.DebugLevel,
+	logrus.InfoLevel,
+	logrus.WarnLevel,
+	logrus.ErrorLevel,
+	logrus.FatalLevel,
+	logrus.PanicLevel,
+}
+
+// Hook is a [logrus.Hook] writing entries into a [logging.Logger]
+type Hook struct {
+	logger *logging.Logger
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook creates a hook writing into the logger. Prefer [New]; when adding the hook
+// to an existing logrus logger such as logrus.StandardLogger(), also discard its output.
+// logger: Logger receiving the entries
+// Returns: Hook for logrus.Logger.AddHook
+func NewHook(logger *logging.Logger) *Hook {
+	return &Hook{logger: logger}
+}
+
+// New returns a logrus logger writing only into the logger.
+// The logrus level is set to the lowest level enabled on the logger at creation time.
+// logger: Logger receiving the entries
+// Returns: logrus logger, which implements logrus.FieldLogger
+func New(logger *logging.Logger) *logrus.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	l.SetFormatter(discardFormatter{})
+	l.SetLevel(minLevel(logger))
+	l.AddHook(NewHook(logger))
+
+	return l
+}
+
+// Levels implements [logrus.Hook]
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements [logrus.Hook]
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := toLevel(entry.Level)
+	if !h.logger.Enabled(ctx, level) {
+		return nil
+	}
+
+	r := slog.NewRecord(entry.Time, level, entry.Message, caller.PC(1, logrusPrefix))
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		r.AddAttrs(toAttr(k, entry.Data[k]))
+	}
+
+	return h.logger.Handler().Handle(ctx, r)
+}
+
+// discardFormatter skips formatting of entries written by the hook only
+type discardFormatter struct{}
+
+// Format implements [logrus.Formatter]
+func (discardFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// toLevel maps logrus levels to logging levels
+func toLevel(level logrus.Level) logging.Level {
+	switch level {
+	case logrus.TraceLevel:
+		return logging.LevelDebug - 4
+	case logrus.DebugLevel:
+		return logging.LevelDebug
+	case logrus.InfoLevel:
+		return logging.LevelInfo
+	case logrus.WarnLevel:
+		return logging.LevelWarn
+	case logrus.ErrorLevel:
+		return logging.LevelError
+	default:
+		return logging.LevelFatal
+	}
+}
+
+// minLevel returns the most verbose logrus level enabled on the logger
+func minLevel(logger *logging.Logger) logrus.Level {
+	for _, level := range levels {
+		if logger.Enabled(context.Background(), toLevel(level)) {
+			return level
+		}
+	}
+
+	return logrus.PanicLevel
+}
+
+// toAttr converts an entry field, logging errors by their message
+func toAttr(key string, v any) slog.Attr {
+	if err, ok := v.(error); ok {
+		return slog.String(key, err.Error())
+	}
+
+	return slog.Any(key, v)
+}
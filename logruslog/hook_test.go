@@ -0,0 +1,100 @@
+package logruslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestHook(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *logrus.Logger)
+		want string
+	}{
+		{"info", func(l *logrus.Logger) { l.Info("msg") }, "level=INFO msg=msg"},
+		{"printf", func(l *logrus.Logger) { l.Warnf("retry %d", 2) }, `level=WARN msg="retry 2"`},
+		{"trace", func(l *logrus.Logger) { l.Trace("msg") }, "level=DEBUG-4 msg=msg"},
+		{
+			"fields sorted",
+			func(l *logrus.Logger) {
+				l.WithFields(logrus.Fields{"b": 2, "a": "1"}).WithField("c", true).Error("msg")
+			},
+			"level=ERROR msg=msg a=1 b=2 c=true",
+		},
+		{"error", func(l *logrus.Logger) { l.WithError(errors.New("boom")).Error("failed") }, "level=ERROR msg=failed error=boom"},
+		{
+			"context attrs",
+			func(l *logrus.Logger) {
+				ctx := logging.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+				l.WithContext(ctx).Info("msg")
+			},
+			"level=INFO msg=msg request_id=r1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(New(newTestLogger(&buf, logging.LevelDebug-4, false)))
+
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLevel(t *testing.T) {
+	tests := []struct {
+		level logging.Level
+		want  logrus.Level
+	}{
+		{logging.LevelDebug - 4, logrus.TraceLevel},
+		{logging.LevelDebug, logrus.DebugLevel},
+		{logging.LevelInfo, logrus.InfoLevel},
+		{logging.LevelWarn, logrus.WarnLevel},
+		{logging.LevelError, logrus.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		if got := New(newTestLogger(&bytes.Buffer{}, tt.level, false)).GetLevel(); got != tt.want {
+			t.Errorf("New() level for %v = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestHookSource(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(newTestLogger(&buf, logging.LevelDebug, true))
+	l.Info("msg")
+	l.WithField("k", "v").Infof("msg %d", 1)
+
+	if got := strings.Count(buf.String(), "hook_test.go"); got != 2 {
+		t.Errorf("output = %q, want source pointing to hook_test.go twice", buf.String())
+	}
+}
+
+// newTestLogger returns a text logger with context attributes and without time
+func newTestLogger(buf *bytes.Buffer, level logging.Level, source bool) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(logging.NewContextHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:     level,
+		AddSource: source,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})))}
+}
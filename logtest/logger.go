@@ -0,0 +1,65 @@
+// Package logtest helps testing code that logs through the logging package.
+//
+// [NewTestLogger] attaches the output of a logger to the running test, and
+// [Recorder] keeps records in memory for assertions:
+//
+//	rec := logtest.NewRecorder()
+//	logger := logtest.NewTestLogger(t, logging.WithMiddleware(rec.Middleware()))
+//
+//	svc.Run(logging.ContextWithLogger(ctx, logger))
+//
+//	rec.AssertLogged(t, logtest.Level(logging.LevelWarn), logtest.Attr("attempt", 3))
+//	rec.AssertNoErrors(t)
+package logtest
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sergei-galichev/logging"
+)
+
+const (
+	// Default configuration constants
+	defaultLevel       = logging.LevelDebug
+	defaultShortSource = true
+)
+
+// testWriter is an io.Writer passing each formatted record to t.Log.
+// t.Helper cannot help here: the frames between the logging call and Write belong to
+// slog and are not helpers, so t.Log always reports this file. The location of the
+// logging call is in the source attribute of the record instead.
+type testWriter struct {
+	t    testing.TB
+	done atomic.Bool // set when the test completed, t.Log would panic afterwards
+}
+
+// Write implements [io.Writer]
+func (w *testWriter) Write(p []byte) (int, error) {
+	if !w.done.Load() {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+
+	return len(p), nil
+}
+
+// NewTestLogger returns a logger writing through t.Log, so that the output is shown
+// with the failing test (or with go test -v). Records are formatted by [logging.NewLogger]
+// at Debug level with a short source pointing to the logging call, since the file:line
+// reported by t.Log is the one of the writer; opts are applied on top of these defaults.
+// Records logged after the test completed are dropped.
+// t: Test or benchmark the output is attached to
+// opts: Variadic list of logger options
+// Returns: Logger for the test
+func NewTestLogger(t testing.TB, opts ...logging.Option) *logging.Logger {
+	w := &testWriter{t: t}
+	t.Cleanup(func() { w.done.Store(true) })
+
+	opts = append([]logging.Option{
+		logging.WithLogLevel(defaultLevel),
+		logging.WithShortSource(defaultShortSource),
+	}, opts...)
+
+	return logging.NewLogger(append(opts, logging.WithWriter(w))...)
+}
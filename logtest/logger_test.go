@@ -0,0 +1,93 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// fakeT records the calls of the code under test to testing.TB
+type fakeT struct {
+	testing.TB
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Log(args ...any) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func TestNewTestLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []logging.Option
+		log  func(l *logging.Logger)
+		want []string
+	}{
+		{
+			"defaults",
+			nil,
+			func(l *logging.Logger) { l.Debug("msg", "k", "v") },
+			[]string{"level=DEBUG", "source=logtest/logger_test.go:", "msg=msg k=v"},
+		},
+		{
+			"options applied",
+			[]logging.Option{logging.WithJSONFormat(true), logging.WithLogLevel(logging.LevelInfo)},
+			func(l *logging.Logger) {
+				l.Debug("dropped")
+				l.Info("msg")
+			},
+			[]string{`"level":"INFO"`, `"msg":"msg"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+
+			tt.log(NewTestLogger(ft, tt.opts...))
+
+			if len(ft.logs) != 1 {
+				t.Fatalf("t.Log calls = %q, want one", ft.logs)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(ft.logs[0], want) {
+					t.Errorf("t.Log(%q), want it to contain %s", ft.logs[0], want)
+				}
+			}
+
+			if strings.HasSuffix(ft.logs[0], "\n") {
+				t.Errorf("t.Log(%q) ends with a newline", ft.logs[0])
+			}
+		})
+	}
+}
+
+func TestNewTestLoggerAfterCleanup(t *testing.T) {
+	ft := &fakeT{}
+
+	logger := NewTestLogger(ft)
+	for _, fn := range ft.cleanups {
+		fn()
+	}
+
+	logger.Info("late")
+
+	if len(ft.logs) != 0 {
+		t.Errorf("t.Log calls after cleanup = %q, want none", ft.logs)
+	}
+}
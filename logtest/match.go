@@ -0,0 +1,239 @@
+package logtest
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// Matcher selects records in [Recorder] queries and assertions
+type Matcher struct {
+	desc  string
+	match func(r Record) bool
+}
+
+// Match reports whether the record matches
+func (m Matcher) Match(r Record) bool {
+	return m.match(r)
+}
+
+// String describes the matcher in failure messages
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// Level matches records logged at exactly the level
+func Level(level logging.Level) Matcher {
+	return Matcher{
+		desc:  "level=" + level.String(),
+		match: func(r Record) bool { return r.Level == level },
+	}
+}
+
+// MinLevel matches records logged at the level or above
+func MinLevel(level logging.Level) Matcher {
+	return Matcher{
+		desc:  "level>=" + level.String(),
+		match: func(r Record) bool { return r.Level >= level },
+	}
+}
+
+// Message matches records whose message equals msg
+func Message(msg string) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("msg=%q", msg),
+		match: func(r Record) bool { return r.Message == msg },
+	}
+}
+
+// MessageContains matches records whose message contains substr
+func MessageContains(substr string) Matcher {
+	return Matcher{
+		desc:  fmt.Sprintf("msg contains %q", substr),
+		match: func(r Record) bool { return strings.Contains(r.Message, substr) },
+	}
+}
+
+// MessageMatches matches records whose message matches the regular expression.
+// It panics if the expression cannot be compiled.
+func MessageMatches(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+
+	return Matcher{
+		desc:  fmt.Sprintf("msg matches %q", pattern),
+		match: func(r Record) bool { return re.MatchString(r.Message) },
+	}
+}
+
+// Attr matches records with an attribute equal to the value. Keys of group members
+// are joined by dots and values are compared by their string form, so that
+// Attr("status", 200) matches slog.Int("status", 200) and slog.String("status", "200").
+func Attr(key string, value any) Matcher {
+	want := slog.AnyValue(value).Resolve().String()
+
+	return Matcher{
+		desc: fmt.Sprintf("%s=%s", key, want),
+		match: func(r Record) bool {
+			v, ok := r.Attr(key)
+
+			return ok && v.String() == want
+		},
+	}
+}
+
+// HasAttr matches records with an attribute, whatever its value
+func HasAttr(key string) Matcher {
+	return Matcher{
+		desc: "has " + key,
+		match: func(r Record) bool {
+			_, ok := r.Attr(key)
+
+			return ok
+		},
+	}
+}
+
+// All matches records matched by every matcher
+func All(matchers ...Matcher) Matcher {
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.desc
+	}
+
+	return Matcher{
+		desc: strings.Join(descs, " "),
+		match: func(r Record) bool {
+			for _, m := range matchers {
+				if !m.match(r) {
+					return false
+				}
+			}
+
+			return true
+		},
+	}
+}
+
+// Find returns the first record matching every matcher
+// matchers: Conditions the record must satisfy, none matching any record
+// Returns: Matching record and whether one was found
+func (r *Recorder) Find(matchers ...Matcher) (Record, bool) {
+	m := All(matchers...)
+
+	for _, rec := range r.Records() {
+		if m.match(rec) {
+			return rec, true
+		}
+	}
+
+	return Record{}, false
+}
+
+// Count returns the number of records matching every matcher
+func (r *Recorder) Count(matchers ...Matcher) int {
+	m := All(matchers...)
+
+	n := 0
+
+	for _, rec := range r.Records() {
+		if m.match(rec) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// AssertLogged reports a test error unless a record matches every matcher
+// t: Test receiving the failure
+// matchers: Conditions the record must satisfy
+// Returns: Whether the assertion held
+func (r *Recorder) AssertLogged(t testing.TB, matchers ...Matcher) bool {
+	t.Helper()
+
+	if _, ok := r.Find(matchers...); !ok {
+		t.Errorf("no record matching [%s]\n%s", All(matchers...), r.dump())
+
+		return false
+	}
+
+	return true
+}
+
+// AssertNotLogged reports a test error if a record matches every matcher
+// t: Test receiving the failure
+// matchers: Conditions no record may satisfy
+// Returns: Whether the assertion held
+func (r *Recorder) AssertNotLogged(t testing.TB, matchers ...Matcher) bool {
+	t.Helper()
+
+	if rec, ok := r.Find(matchers...); ok {
+		t.Errorf("unexpected record matching [%s]: %s", All(matchers...), rec)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertNoErrors reports a test error if a record was logged at Error level or above
+// t: Test receiving the failure
+// Returns: Whether the assertion held
+func (r *Recorder) AssertNoErrors(t testing.TB) bool {
+	t.Helper()
+
+	return r.AssertNotLogged(t, MinLevel(logging.LevelError))
+}
+
+// AssertInOrder reports a test error unless records matching each matcher were
+// logged in the given order; other records may be logged in between.
+// Use [All] to combine several conditions into one expectation.
+// t: Test receiving the failure
+// matchers: Expectations in logging order
+// Returns: Whether the assertion held
+func (r *Recorder) AssertInOrder(t testing.TB, matchers ...Matcher) bool {
+	t.Helper()
+
+	records := r.Records()
+	next := 0
+
+	for _, m := range matchers {
+		found := false
+
+		for next < len(records) && !found {
+			found = m.match(records[next])
+			next++
+		}
+
+		if !found {
+			t.Errorf("no record matching [%s] after the previous expectations\n%s", m, r.dump())
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// dump lists the recorded records for failure messages
+func (r *Recorder) dump() string {
+	records := r.Records()
+	if len(records) == 0 {
+		return "no records"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("records:")
+
+	for _, rec := range records {
+		b.WriteString("\n\t")
+		b.WriteString(rec.String())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package logtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestMatchers(t *testing.T) {
+	rec := NewRecorder()
+	rec.Logger().Warn("retry failed: timeout", "attempt", 3, logging.Err(errors.New("boom")))
+
+	record := rec.Records()[0]
+
+	tests := []struct {
+		name    string
+		matcher Matcher
+		want    bool
+	}{
+		{"level", Level(logging.LevelWarn), true},
+		{"other level", Level(logging.LevelError), false},
+		{"min level", MinLevel(logging.LevelInfo), true},
+		{"min level above", MinLevel(logging.LevelError), false},
+		{"message", Message("retry failed: timeout"), true},
+		{"message prefix", Message("retry failed"), false},
+		{"message contains", MessageContains("timeout"), true},
+		{"message matches", MessageMatches(`^retry .*: \w+$`), true},
+		{"attr int", Attr("attempt", 3), true},
+		{"attr string form", Attr("attempt", "3"), true},
+		{"attr other value", Attr("attempt", 4), false},
+		{"attr error", Attr("error", "boom"), true},
+		{"has attr", HasAttr("error"), true},
+		{"missing attr", HasAttr("status"), false},
+		{"all", All(Level(logging.LevelWarn), Attr("attempt", 3)), true},
+		{"all one failing", All(Level(logging.LevelWarn), Attr("attempt", 4)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(record); got != tt.want {
+				t.Errorf("%s.Match(%s) = %v, want %v", tt.matcher, record, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	rec := NewRecorder()
+	logger := rec.Logger()
+	logger.Info("start")
+	logger.Debug("step", "n", 1)
+	logger.Warn("slow", "n", 2)
+	logger.Info("done")
+
+	tests := []struct {
+		name   string
+		assert func(t *fakeT) bool
+		want   bool
+	}{
+		{"logged", func(t *fakeT) bool { return rec.AssertLogged(t, Level(logging.LevelWarn), Attr("n", 2)) }, true},
+		{"not logged fails", func(t *fakeT) bool { return rec.AssertLogged(t, Level(logging.LevelError)) }, false},
+		{"not logged", func(t *fakeT) bool { return rec.AssertNotLogged(t, Message("stop")) }, true},
+		{"logged fails", func(t *fakeT) bool { return rec.AssertNotLogged(t, Message("start")) }, false},
+		{"no errors", func(t *fakeT) bool { return rec.AssertNoErrors(t) }, true},
+		{
+			"in order",
+			func(t *fakeT) bool {
+				return rec.AssertInOrder(t, Message("start"), All(Message("slow"), Attr("n", 2)), Message("done"))
+			},
+			true,
+		},
+		{"out of order", func(t *fakeT) bool { return rec.AssertInOrder(t, Message("done"), Message("start")) }, false},
+		{"same record twice", func(t *fakeT) bool { return rec.AssertInOrder(t, Message("slow"), Message("slow")) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeT{}
+
+			got := tt.assert(ft)
+			if got != tt.want || (len(ft.errors) == 0) != tt.want {
+				t.Errorf("assertion = %v with errors %q, want %v", got, ft.errors, tt.want)
+			}
+		})
+	}
+
+	if got := rec.Count(HasAttr("n")); got != 2 {
+		t.Errorf("Count(HasAttr(n)) = %d, want 2", got)
+	}
+}
@@ -0,0 +1,202 @@
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// Record is a log record captured by a [Recorder]
+type Record struct {
+	Time    time.Time
+	Level   logging.Level
+	Message string
+	Attrs   []slog.Attr // Resolved attributes; group members have dotted keys such as "http.status"
+	PC      uintptr
+}
+
+// Attr returns the value of the last attribute with the key
+// key: Attribute key, with group names joined by dots
+// Returns: Attribute value and whether it was found
+func (r Record) Attr(key string) (slog.Value, bool) {
+	for i := len(r.Attrs) - 1; i >= 0; i-- {
+		if r.Attrs[i].Key == key {
+			return r.Attrs[i].Value, true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// String formats the record as "LEVEL message key=value ..." for failure messages
+func (r Record) String() string {
+	var b strings.Builder
+
+	b.WriteString(r.Level.String())
+	b.WriteString(" ")
+	b.WriteString(r.Message)
+
+	for _, a := range r.Attrs {
+		b.WriteString(" ")
+		b.WriteString(a.String())
+	}
+
+	return b.String()
+}
+
+// recordStore holds the records shared by a Recorder and the handlers derived from it
+type recordStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// Recorder is a [slog.Handler] keeping every record in memory for assertions.
+// Handlers returned by WithAttrs and WithGroup share the records of their parent.
+type Recorder struct {
+	store  *recordStore
+	attrs  []slog.Attr // flattened attributes added by WithAttrs
+	prefix string      // dotted group prefix added by WithGroup
+}
+
+var _ slog.Handler = (*Recorder)(nil)
+
+// NewRecorder creates an empty recorder
+// Returns: Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{store: &recordStore{}}
+}
+
+// Logger returns a logger recording into the recorder, with context attributes
+// Returns: Logger
+func (r *Recorder) Logger() *logging.Logger {
+	return &logging.Logger{Logger: slog.New(logging.NewContextHandler(r))}
+}
+
+// Middleware returns a middleware recording the records passed to the next handler,
+// for use with [logging.WithMiddleware]. Only records enabled on the next handler
+// are recorded; context attributes are included.
+// Returns: Middleware
+func (r *Recorder) Middleware() logging.Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return &recordingHandler{recorder: logging.NewContextHandler(r), next: next}
+	}
+}
+
+// Records returns a copy of the recorded records in logging order
+func (r *Recorder) Records() []Record {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return slices.Clone(r.store.records)
+}
+
+// Reset removes all recorded records
+func (r *Recorder) Reset() {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.records = nil
+}
+
+// Enabled implements [slog.Handler]; every level is recorded
+func (r *Recorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements [slog.Handler]
+func (r *Recorder) Handle(_ context.Context, rec slog.Record) error {
+	attrs := slices.Clone(r.attrs)
+
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = appendFlat(attrs, r.prefix, a)
+
+		return true
+	})
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.records = append(r.store.records, Record{
+		Time:    rec.Time,
+		Level:   rec.Level,
+		Message: rec.Message,
+		Attrs:   attrs,
+		PC:      rec.PC,
+	})
+
+	return nil
+}
+
+// WithAttrs implements [slog.Handler]
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	flat := slices.Clip(r.attrs)
+	for _, a := range attrs {
+		flat = appendFlat(flat, r.prefix, a)
+	}
+
+	return &Recorder{store: r.store, attrs: flat, prefix: r.prefix}
+}
+
+// WithGroup implements [slog.Handler]
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+
+	return &Recorder{store: r.store, attrs: r.attrs, prefix: r.prefix + name + "."}
+}
+
+// appendFlat resolves the attribute and appends it, flattening groups into dotted keys
+func appendFlat(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, slog.Attr{Key: prefix + a.Key, Value: a.Value})
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+
+	for _, member := range a.Value.Group() {
+		attrs = appendFlat(attrs, prefix, member)
+	}
+
+	return attrs
+}
+
+// recordingHandler passes records to the next handler and to a recorder
+type recordingHandler struct {
+	recorder slog.Handler
+	next     slog.Handler
+}
+
+// Enabled implements [slog.Handler]
+func (h *recordingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *recordingHandler) Handle(ctx context.Context, r slog.Record) error {
+	_ = h.recorder.Handle(ctx, r.Clone())
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordingHandler{recorder: h.recorder.WithAttrs(attrs), next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements [slog.Handler]
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return &recordingHandler{recorder: h.recorder.WithGroup(name), next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,129 @@
+package logtest
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestRecorder(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *logging.Logger)
+		want string
+	}{
+		{"message and attrs", func(l *logging.Logger) { l.Warn("msg", "k", "v", "n", 1) }, "WARN msg k=v n=1"},
+		{"logger attrs first", func(l *logging.Logger) { l.With("svc", "api").Info("msg", "k", "v") }, "INFO msg svc=api k=v"},
+		{
+			"groups flattened",
+			func(l *logging.Logger) {
+				l.WithGroup("http").Info("msg", slog.Group("req", "method", "GET"), "status", 200)
+			},
+			"INFO msg http.req.method=GET http.status=200",
+		},
+		{
+			"values resolved",
+			func(l *logging.Logger) { l.Info("msg", logging.Sensitive("token", "t0k3n")) },
+			"INFO msg token=[REDACTED]",
+		},
+		{
+			"context attrs",
+			func(l *logging.Logger) {
+				l.InfoContext(logging.ContextWithAttrs(context.Background(), slog.String("request_id", "r1")), "msg")
+			},
+			"INFO msg request_id=r1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder()
+
+			tt.log(rec.Logger())
+
+			records := rec.Records()
+			if len(records) != 1 {
+				t.Fatalf("Records() = %v, want one record", records)
+			}
+
+			if got := records[0].String(); got != tt.want {
+				t.Errorf("record = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorderMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+
+	rec := NewRecorder()
+	logger := logging.NewLogger(
+		logging.WithWriter(&buf),
+		logging.WithLogLevel(logging.LevelInfo),
+		logging.WithMiddleware(rec.Middleware()),
+	)
+
+	ctx := logging.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+	logger.DebugContext(ctx, "dropped")
+	logger.With("svc", "api").InfoContext(ctx, "kept")
+
+	records := rec.Records()
+	if len(records) != 1 || records[0].String() != "INFO kept svc=api request_id=r1" {
+		t.Errorf("Records() = %v, want the Info record with context and logger attrs", records)
+	}
+
+	if !strings.Contains(buf.String(), "msg=kept") {
+		t.Errorf("output = %q, want the record passed to the next handler", buf.String())
+	}
+
+	rec.Reset()
+
+	if got := rec.Records(); len(got) != 0 {
+		t.Errorf("Records() after Reset() = %v, want none", got)
+	}
+}
+
+func TestRecorderConformance(t *testing.T) {
+	rec := NewRecorder()
+
+	err := slogtest.TestHandler(rec, func() []map[string]any {
+		var results []map[string]any
+
+		for _, r := range rec.Records() {
+			m := map[string]any{slog.LevelKey: r.Level, slog.MessageKey: r.Message}
+			if !r.Time.IsZero() {
+				m[slog.TimeKey] = r.Time
+			}
+
+			// undo the flattening of groups into dotted keys
+			for _, a := range r.Attrs {
+				keys := strings.Split(a.Key, ".")
+				group := m
+
+				for _, k := range keys[:len(keys)-1] {
+					sub, ok := group[k].(map[string]any)
+					if !ok {
+						sub = map[string]any{}
+						group[k] = sub
+					}
+
+					group = sub
+				}
+
+				group[keys[len(keys)-1]] = a.Value.Any()
+			}
+
+			results = append(results, m)
+		}
+
+		return results
+	})
+	if err != nil {
+		t.Errorf("slogtest: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// stringerValue defers the String call until the record is handled
+type stringerValue struct {
+	v fmt.Stringer
+}
+
+// LogValue implements [slog.LogValuer]
+func (s stringerValue) LogValue() slog.Value {
+	if isNil(s.v) {
+		return slog.StringValue("nil")
+	}
+
+	return slog.StringValue(s.v.String())
+}
+
+// textValue defers the MarshalText call until the record is handled
+type textValue struct {
+	v encoding.TextMarshaler
+}
+
+// LogValue implements [slog.LogValuer]
+func (t textValue) LogValue() (val slog.Value) {
+	if isNil(t.v) {
+		return slog.StringValue("nil")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			val = slog.StringValue(fmt.Sprintf("!PANIC: %v", r))
+		}
+	}()
+
+	text, err := t.v.MarshalText()
+	if err != nil {
+		return slog.StringValue("!ERROR: " + err.Error())
+	}
+
+	return slog.StringValue(string(text))
+}
+
+// Stringer creates an attribute from a fmt.Stringer.
+// The String method is called only when a handler processes the record.
+// key: The attribute key
+// val: The value. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func Stringer(key string, val fmt.Stringer) slog.Attr {
+	return slog.Any(key, stringerValue{v: val})
+}
+
+// Text creates an attribute from an encoding.TextMarshaler.
+// The MarshalText method is called only when a handler processes the record;
+// an error or panic is logged as "!ERROR: ..." or "!PANIC: ..." instead of the value.
+// key: The attribute key
+// val: The value. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func Text(key string, val encoding.TextMarshaler) slog.Attr {
+	return slog.Any(key, textValue{v: val})
+}
+
+// RawJSON creates an attribute from a pre-encoded JSON document.
+// JSON handlers embed valid documents verbatim instead of escaping them as a string;
+// invalid documents are logged as a plain string prefixed with "!INVALID JSON: ".
+// key: The attribute key
+// raw: The JSON document. If nil, the attribute will be logged as "nil"
+// Returns: A structured log attribute
+func RawJSON(key string, raw json.RawMessage) slog.Attr {
+	if raw == nil {
+		return slog.String(key, "nil")
+	}
+
+	if !json.Valid(raw) {
+		return slog.String(key, "!INVALID JSON: "+string(raw))
+	}
+
+	return slog.Any(key, raw)
+}
+
+// isNil reports whether v is nil or an interface holding a nil pointer
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+// stringerMock counts String calls
+type stringerMock struct {
+	calls *int
+}
+
+func (s stringerMock) String() string {
+	*s.calls++
+
+	return "stringer"
+}
+
+// textMock is an encoding.TextMarshaler with a configurable result
+type textMock struct {
+	text string
+	err  error
+}
+
+func (m *textMock) MarshalText() ([]byte, error) {
+	if m.text == "panic" {
+		panic("boom")
+	}
+
+	return []byte(m.text), m.err
+}
+
+func TestStringer(t *testing.T) {
+	var nilIP *net.IPAddr
+
+	tests := []struct {
+		name string
+		val  interface{ String() string }
+		want string
+	}{
+		{"nil interface", nil, "nil"},
+		{"nil pointer", nilIP, "nil"},
+		{"value", net.IPv4(127, 0, 0, 1), "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Stringer("key", tt.val).Value.Resolve()
+			if got.String() != tt.want {
+				t.Errorf("Stringer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringerLazy(t *testing.T) {
+	calls := 0
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: LevelInfo}))
+	logger.Debug("msg", Stringer("key", stringerMock{calls: &calls}))
+
+	if calls != 0 {
+		t.Errorf("Stringer() called String %d times for a disabled level", calls)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		val  *textMock
+		want string
+	}{
+		{"nil pointer", nil, "nil"},
+		{"value", &textMock{text: "text"}, "text"},
+		{"error", &textMock{err: errors.New("bad value")}, "!ERROR: bad value"},
+		{"panic", &textMock{text: "panic"}, "!PANIC: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Text("key", tt.val).Value.Resolve()
+			if got.String() != tt.want {
+				t.Errorf("Text() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  json.RawMessage
+		want string
+	}{
+		{"nil", nil, `"key":"nil"`},
+		{"object", json.RawMessage(`{"a": 1}`), `"key":{"a":1}`},
+		{"array", json.RawMessage(`[1,2]`), `"key":[1,2]`},
+		{"invalid", json.RawMessage(`{"a":`), `"key":"!INVALID JSON: {\"a\":"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			slog.New(slog.NewJSONHandler(&buf, nil)).LogAttrs(context.Background(), LevelInfo, "msg", RawJSON("key", tt.raw))
+
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("RawJSON() output = %s, want it to contain %s", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// objectTagName is the struct tag consulted by [Object]
+	objectTagName = "log"
+
+	// defaultObjectMaxDepth limits how deep [Object] descends into nested structs
+	defaultObjectMaxDepth = 8
+
+	// redactedValue replaces the value of fields tagged with `log:",redact"`
+	redactedValue = "[REDACTED]"
+
+	// cycleValue is logged in place of a pointer that refers back to an enclosing struct
+	cycleValue = "[cycle]"
+
+	// maxDepthValue is logged in place of a struct nested deeper than the depth limit
+	maxDepthValue = "[max depth]"
+)
+
+// objectField describes a single loggable struct field
+type objectField struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	redact    bool
+	inline    bool
+}
+
+var (
+	// objectFieldsCache caches parsed field descriptions per struct type
+	objectFieldsCache sync.Map // map[reflect.Type][]objectField
+
+	// objectTaggedCache caches whether a type reaches a field with a `log` tag
+	objectTaggedCache sync.Map // map[reflect.Type]bool
+)
+
+// objectValue defers the reflection walk until the record is handled
+type objectValue struct {
+	v        any
+	maxDepth int
+}
+
+// Object creates an attribute that logs a struct as a nested group.
+// Fields are discovered via reflection (cached per type) and honor the `log` tag:
+//
+//	Name    string `log:"name"`      // rename the field
+//	Pass    string `log:"-"`         // skip the field
+//	Note    string `log:",omitempty"` // skip the field when it holds a zero value
+//	Token   string `log:",redact"`    // log "[REDACTED]" instead of the value
+//	Address Addr   `log:",inline"`    // merge nested fields into the parent group
+//
+// Embedded structs without an explicit name are inlined, nil pointers are logged
+// as "nil", cycles are logged as "[cycle]" and nesting is limited to 8 levels.
+// Slices, arrays and maps holding structs are logged as groups keyed by index or
+// map key, so that tags of their elements are honored as well. Unexported values
+// that reflection cannot format, such as a time.Time, are skipped.
+// The struct is walked only when a handler actually processes the record.
+// key: The attribute key
+// v: Struct or pointer to struct
+// Returns: A structured log attribute
+func Object(key string, v any) slog.Attr {
+	return slog.Any(key, objectValue{v: v, maxDepth: defaultObjectMaxDepth})
+}
+
+// ObjectDepth works like [Object] with a custom nesting limit.
+// key: The attribute key
+// v: Struct or pointer to struct
+// maxDepth: Maximum number of nested struct levels to log
+// Returns: A structured log attribute
+func ObjectDepth(key string, v any, maxDepth int) slog.Attr {
+	return slog.Any(key, objectValue{v: v, maxDepth: maxDepth})
+}
+
+// LogValue implements [slog.LogValuer]
+func (o objectValue) LogValue() slog.Value {
+	w := objectWalker{
+		maxDepth: o.maxDepth,
+		visited:  make(map[uintptr]struct{}),
+	}
+
+	v, _ := w.value(reflect.ValueOf(o.v), 0)
+
+	return v
+}
+
+// objectWalker holds the state of a single reflection walk
+type objectWalker struct {
+	maxDepth int
+	visited  map[uintptr]struct{}
+}
+
+// value converts an arbitrary reflected value into a slog.Value. It reports false
+// for values read through unexported fields that cannot be formatted, such as a
+// time.Time, whose methods reflection cannot call; they are skipped like encoding/json does.
+func (w *objectWalker) value(rv reflect.Value, depth int) (slog.Value, bool) {
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	// checked first, so that methods such as Error are not called on typed nils
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return slog.StringValue("nil"), true
+	}
+
+	if rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case slog.LogValuer:
+			return slog.AnyValue(v), true
+		case error:
+			return slog.StringValue(v.Error()), true
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.Elem().Kind() != reflect.Struct {
+			return w.value(rv.Elem(), depth)
+		}
+
+		ptr := rv.Pointer()
+		if _, ok := w.visited[ptr]; ok {
+			return slog.StringValue(cycleValue), true
+		}
+
+		w.visited[ptr] = struct{}{}
+		defer delete(w.visited, ptr)
+
+		return w.value(rv.Elem(), depth)
+	case reflect.Struct:
+		if isOpaqueStruct(rv.Type()) {
+			return scalarValue(rv)
+		}
+
+		if depth >= w.maxDepth {
+			return slog.StringValue(maxDepthValue), true
+		}
+
+		return slog.GroupValue(w.structAttrs(rv, depth+1)...), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rv.Len() == 0 || !needsWalk(rv.Type().Elem(), nil) {
+			return scalarValue(rv)
+		}
+
+		if depth >= w.maxDepth {
+			return slog.StringValue(maxDepthValue), true
+		}
+
+		if rv.Kind() != reflect.Array {
+			ptr := rv.Pointer()
+			if _, ok := w.visited[ptr]; ok {
+				return slog.StringValue(cycleValue), true
+			}
+
+			w.visited[ptr] = struct{}{}
+			defer delete(w.visited, ptr)
+		}
+
+		return slog.GroupValue(w.elementAttrs(rv, depth+1)...), true
+	}
+
+	return scalarValue(rv)
+}
+
+// elementAttrs converts the elements of a slice, array or map into attributes
+// keyed by index or by map key, in key order
+func (w *objectWalker) elementAttrs(rv reflect.Value, depth int) []slog.Attr {
+	attrs := make([]slog.Attr, 0, rv.Len())
+
+	if rv.Kind() != reflect.Map {
+		for i := range rv.Len() {
+			if v, ok := w.value(rv.Index(i), depth); ok {
+				attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: v})
+			}
+		}
+
+		return attrs
+	}
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		if v, ok := w.value(iter.Value(), depth); ok {
+			attrs = append(attrs, slog.Attr{Key: mapKey(iter.Key()), Value: v})
+		}
+	}
+
+	slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+
+	return attrs
+}
+
+// mapKey formats a map key, including keys read through unexported fields
+func mapKey(rv reflect.Value) string {
+	if rv.CanInterface() {
+		return fmt.Sprint(rv.Interface())
+	}
+
+	if v, ok := scalarValue(rv); ok {
+		return v.String()
+	}
+
+	return rv.Type().String()
+}
+
+// needsWalk reports whether values of a type may hold structs whose fields
+// must be logged one by one rather than formatted as a whole
+func needsWalk(t reflect.Type, seen map[reflect.Type]struct{}) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return !isOpaqueStruct(t)
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		if _, ok := seen[t]; ok {
+			return false
+		}
+
+		if seen == nil {
+			seen = make(map[reflect.Type]struct{})
+		}
+
+		seen[t] = struct{}{}
+
+		return needsWalk(t.Elem(), seen)
+	}
+
+	return false
+}
+
+// scalarValue converts a non-struct value into a slog.Value, including basic values
+// read through unexported fields; it reports false for other unexported values
+func scalarValue(rv reflect.Value) (slog.Value, bool) {
+	if rv.CanInterface() {
+		return slog.AnyValue(rv.Interface()), true
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return slog.BoolValue(rv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return slog.Uint64Value(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(rv.Float()), true
+	case reflect.String:
+		return slog.StringValue(rv.String()), true
+	}
+
+	return slog.Value{}, false
+}
+
+// structAttrs converts the exported fields of a struct into attributes
+func (w *objectWalker) structAttrs(rv reflect.Value, depth int) []slog.Attr {
+	fields := objectFields(rv.Type())
+	attrs := make([]slog.Attr, 0, len(fields))
+
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			if !f.omitEmpty {
+				attrs = append(attrs, slog.String(f.name, "nil"))
+			}
+
+			continue
+		}
+
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if f.redact {
+			attrs = append(attrs, slog.String(f.name, redactedValue))
+
+			continue
+		}
+
+		val, ok := w.value(fv, depth)
+		if !ok {
+			continue
+		}
+
+		if f.inline && val.Kind() == slog.KindGroup {
+			attrs = append(attrs, val.Group()...)
+
+			continue
+		}
+
+		attrs = append(attrs, slog.Attr{Key: f.name, Value: val})
+	}
+
+	return attrs
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports false
+// instead of panicking when an embedded pointer along the path is nil
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			rv = rv.Elem()
+		}
+
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+// isOpaqueStruct reports whether a struct type should be logged as a whole
+// rather than field by field (for example time.Time). Structs without exported
+// fields are opaque unless they reach a field with a `log` tag, which formatting
+// them as a whole would ignore.
+func isOpaqueStruct(t reflect.Type) bool {
+	return !hasExportedFields(t) && !isTagged(t)
+}
+
+// isTagged reports whether a type has, or reaches through its fields, pointers,
+// slices, arrays and maps, a struct field with a `log` tag
+func isTagged(t reflect.Type) bool {
+	if cached, ok := objectTaggedCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	tagged := reachesTag(t, make(map[reflect.Type]struct{}))
+	objectTaggedCache.Store(t, tagged)
+
+	return tagged
+}
+
+// reachesTag implements isTagged, seen guards against recursive types
+func reachesTag(t reflect.Type, seen map[reflect.Type]struct{}) bool {
+	if _, ok := seen[t]; ok {
+		return false
+	}
+
+	seen[t] = struct{}{}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesTag(t.Elem(), seen)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			if _, ok := sf.Tag.Lookup(objectTagName); ok || reachesTag(sf.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// objectFields returns the cached field descriptions for a struct type
+func objectFields(t reflect.Type) []objectField {
+	if cached, ok := objectFieldsCache.Load(t); ok {
+		return cached.([]objectField)
+	}
+
+	fields := parseObjectFields(t, nil, map[reflect.Type]struct{}{t: {}})
+
+	cached, _ := objectFieldsCache.LoadOrStore(t, fields)
+
+	return cached.([]objectField)
+}
+
+// parseObjectFields collects loggable fields of a struct type.
+// Embedded structs without an explicit tag name are flattened into the result,
+// seen guards against types that embed themselves through a pointer.
+// Structs without exported fields are only walked when they reach a `log` tag;
+// their unexported fields are then logged, as formatting them would have done.
+func parseObjectFields(t reflect.Type, parent []int, seen map[reflect.Type]struct{}) []objectField {
+	fields := make([]objectField, 0, t.NumField())
+	unexported := !hasExportedFields(t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		tag := sf.Tag.Get(objectTagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if _, ok := seen[ft]; ok {
+				continue
+			}
+
+			if !sf.IsExported() && !hasExportedFields(ft) && !(unexported && isTagged(ft)) {
+				continue
+			}
+
+			seen[ft] = struct{}{}
+			fields = append(fields, parseObjectFields(ft, index, seen)...)
+			delete(seen, ft)
+
+			continue
+		}
+
+		if !sf.IsExported() && !unexported {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := objectField{
+			index: index,
+			name:  name,
+		}
+
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "omitempty":
+				f.omitEmpty = true
+			case "redact":
+				f.redact = true
+			case "inline":
+				f.inline = true
+			}
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// hasExportedFields reports whether a struct type has at least one exported field
+func hasExportedFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
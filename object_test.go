@@ -0,0 +1,221 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type objectAddress struct {
+	City string `log:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+type objectBase struct {
+	ID int `log:"id"`
+}
+
+type objectUser struct {
+	objectBase
+	Name     string         `log:"name"`
+	Password string         `log:"password,redact"`
+	Internal string         `log:"-"`
+	Nickname *string        `log:"nickname"`
+	Address  objectAddress  `log:",inline"`
+	Manager  *objectUser    `log:"manager,omitempty"`
+	Created  time.Time      `log:"created,omitempty"`
+	Tags     []string       `log:"tags,omitempty"`
+	Extra    map[string]int `log:"extra,omitempty"`
+}
+
+type objectCredentials struct {
+	Name string `log:"name"`
+	Pass string `log:"pass,redact"`
+}
+
+type objectAccounts struct {
+	Users []objectCredentials           `log:"users"`
+	ByKey map[string]*objectCredentials `log:"by_key"`
+	Any   []any                         `log:"any"`
+}
+
+// objectSealed has no exported fields, but its tags must still be honored
+type objectSealed struct {
+	user string `log:"user"`
+	pw   string `log:"pw,redact"`
+}
+
+// objectStamped has an unexported field that reflection cannot format
+type objectStamped struct {
+	user string    `log:"user"`
+	at   time.Time `log:"at"`
+}
+
+// objectError dereferences its receiver, so it must not be called on nil
+type objectError struct {
+	msg string
+}
+
+func (e *objectError) Error() string { return e.msg }
+
+type objectResult struct {
+	Err error `log:"err"`
+}
+
+type objectNode struct {
+	Name string      `log:"name"`
+	Next *objectNode `log:"next"`
+}
+
+func TestObject(t *testing.T) {
+	manager := &objectUser{Name: "bob"}
+
+	tests := []struct {
+		name string
+		val  any
+		want string
+	}{
+		{
+			"flat struct",
+			objectAddress{City: "Paris"},
+			"obj.city=Paris",
+		},
+		{
+			"tags, embedded and inline",
+			objectUser{
+				objectBase: objectBase{ID: 7},
+				Name:       "alice",
+				Password:   "secret",
+				Internal:   "skip",
+				Address:    objectAddress{City: "Rome", Zip: "00100"},
+			},
+			"obj.id=7 obj.name=alice obj.password=[REDACTED] obj.nickname=nil obj.city=Rome obj.zip=00100",
+		},
+		{
+			"pointer to struct with nested pointer",
+			&objectUser{Name: "alice", Nickname: stringPtr("al"), Manager: manager},
+			"obj.id=0 obj.name=alice obj.password=[REDACTED] obj.nickname=al obj.city=\"\" " +
+				"obj.manager.id=0 obj.manager.name=bob obj.manager.password=[REDACTED] obj.manager.nickname=nil obj.manager.city=\"\"",
+		},
+		{
+			"nil pointer",
+			(*objectUser)(nil),
+			"obj=nil",
+		},
+		{
+			"slice of structs",
+			[]objectCredentials{{Name: "a", Pass: "hunter2"}},
+			"obj.0.name=a obj.0.pass=[REDACTED]",
+		},
+		{
+			"slices, maps and interfaces holding structs",
+			objectAccounts{
+				Users: []objectCredentials{{Name: "a", Pass: "hunter2"}, {Name: "b", Pass: "pw1"}},
+				ByKey: map[string]*objectCredentials{"k": {Name: "c", Pass: "pw2"}, "j": nil},
+				Any:   []any{objectCredentials{Name: "d", Pass: "pw3"}, 1},
+			},
+			"obj.users.0.name=a obj.users.0.pass=[REDACTED] obj.users.1.name=b obj.users.1.pass=[REDACTED] " +
+				"obj.by_key.j=nil obj.by_key.k.name=c obj.by_key.k.pass=[REDACTED] " +
+				"obj.any.0.name=d obj.any.0.pass=[REDACTED] obj.any.1=1",
+		},
+		{
+			"array of structs",
+			[1]objectCredentials{{Name: "a", Pass: "hunter2"}},
+			"obj.0.name=a obj.0.pass=[REDACTED]",
+		},
+		{
+			"scalar slices and empty containers",
+			objectAccounts{Users: []objectCredentials{}},
+			"obj.users=[] obj.by_key=map[] obj.any=[]",
+		},
+		{
+			"struct without exported fields",
+			objectSealed{user: "bob", pw: "hunter2"},
+			"obj.user=bob obj.pw=[REDACTED]",
+		},
+		{
+			"unexported fields that cannot be formatted are skipped",
+			objectStamped{user: "bob", at: time.Unix(0, 0)},
+			"obj.user=bob",
+		},
+		{
+			"typed nil error in an interface field",
+			objectResult{Err: (*objectError)(nil)},
+			"obj.err=nil",
+		},
+		{
+			"map of structs without exported fields",
+			map[string]objectSealed{"k": {user: "bob", pw: "hunter2"}},
+			"obj.k.user=bob obj.k.pw=[REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderText(t, Object("obj", tt.val))
+			if got != tt.want {
+				t.Errorf("Object() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectCycle(t *testing.T) {
+	a := &objectNode{Name: "a"}
+	b := &objectNode{Name: "b", Next: a}
+	a.Next = b
+
+	got := renderText(t, Object("obj", a))
+	want := "obj.name=a obj.next.name=b obj.next.next=[cycle]"
+
+	if got != want {
+		t.Errorf("Object() = %q, want %q", got, want)
+	}
+}
+
+func TestObjectSliceCycle(t *testing.T) {
+	s := []any{nil}
+	s[0] = s
+
+	got := renderText(t, Object("obj", s))
+	want := "obj.0=[cycle]"
+
+	if got != want {
+		t.Errorf("Object() = %q, want %q", got, want)
+	}
+}
+
+func TestObjectDepth(t *testing.T) {
+	n := &objectNode{Name: "a", Next: &objectNode{Name: "b", Next: &objectNode{Name: "c"}}}
+
+	got := renderText(t, ObjectDepth("obj", n, 2))
+	want := "obj.name=a obj.next.name=b obj.next.next=\"[max depth]\""
+
+	if got != want {
+		t.Errorf("ObjectDepth() = %q, want %q", got, want)
+	}
+}
+
+// renderText renders attributes through a text handler without time and level
+func renderText(t *testing.T, attrs ...slog.Attr) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+
+	slog.New(h).LogAttrs(context.Background(), LevelInfo, "", attrs...)
+
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,288 @@
+// Package otellog correlates log records with OpenTelemetry traces.
+// It provides a handler middleware that adds the trace and span identifiers
+// of the active span to records logged with the *Context methods and can
+// record Error-level logs as span events.
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sergei-galichev/logging"
+	"github.com/sergei-galichev/logging/internal/topattrs"
+)
+
+const (
+	// Default configuration constants
+	defaultTraceIDKey    = "trace_id"
+	defaultSpanIDKey     = "span_id"
+	defaultTraceFlagsKey = "trace_flags"
+	defaultSpanEvents    = false
+	defaultEventLevel    = logging.LevelError
+
+	// eventName is the name of span events created from log records
+	eventName = "log"
+)
+
+// Options contains configuration for the trace correlation handler
+type Options struct {
+	TraceIDKey    string        // Attribute key for the trace ID, empty to omit it
+	SpanIDKey     string        // Attribute key for the span ID, empty to omit it
+	TraceFlagsKey string        // Attribute key for the trace flags, empty to omit them
+	SpanEvents    bool          // Whether to record logs as span events
+	EventLevel    logging.Level // Minimum level recorded as span events
+
+	// Sanitize redacts and scrubs span event attributes, which do not go through
+	// the ReplaceAttr of the logger; [logging.Sanitizer] with default options by default
+	Sanitize func(groups []string, a slog.Attr) slog.Attr
+}
+
+// Option defines a function type for configuring Options
+type Option func(*Options)
+
+// Handler is a [slog.Handler] middleware adding trace correlation attributes.
+// The attributes are added at the top level of records, even after WithGroup, by
+// rebuilding the handlers below it; the rebuilt handler is reused for the following
+// records of the same span. Span events carry the attributes added via With as well.
+type Handler struct {
+	next   slog.Handler
+	opts   *Options
+	groups []string             // groups of span event attributes
+	attrs  []attribute.KeyValue // span event attributes added via With
+	chain  topattrs.Chain       // groups and attributes added since the first group
+}
+
+// NewHandler wraps a handler with trace correlation
+// next: Handler receiving the enriched records
+// opts: Variadic list of configuration options
+// Returns: Trace-aware handler
+func NewHandler(next slog.Handler, opts ...Option) *Handler {
+	config := &Options{
+		TraceIDKey:    defaultTraceIDKey,
+		SpanIDKey:     defaultSpanIDKey,
+		TraceFlagsKey: defaultTraceFlagsKey,
+		SpanEvents:    defaultSpanEvents,
+		EventLevel:    defaultEventLevel,
+		Sanitize:      logging.Sanitizer(),
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &Handler{
+		next: next,
+		opts: config,
+	}
+}
+
+// Middleware returns the handler as a [logging.Middleware] for [logging.WithMiddleware]
+// opts: Variadic list of configuration options
+// Returns: Middleware function
+func Middleware(opts ...Option) logging.Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewHandler(next, opts...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	span := trace.SpanFromContext(ctx)
+	sc := span.SpanContext()
+
+	if !sc.IsValid() {
+		return h.next.Handle(ctx, r)
+	}
+
+	if h.opts.SpanEvents && r.Level >= h.opts.EventLevel && span.IsRecording() {
+		span.AddEvent(eventName, trace.WithTimestamp(r.Time), trace.WithAttributes(h.eventAttrs(r)...))
+	}
+
+	if h.opts.TraceIDKey == "" && h.opts.SpanIDKey == "" && h.opts.TraceFlagsKey == "" {
+		return h.next.Handle(ctx, r)
+	}
+
+	if h.chain.Grouped() {
+		// the rebuilt handler is reused for the records of the same span
+		key := spanKey{sc.TraceID(), sc.SpanID(), sc.TraceFlags()}
+
+		return h.chain.CachedHandler(key, func() []slog.Attr { return h.traceAttrs(sc) }).Handle(ctx, r)
+	}
+
+	r = r.Clone()
+	r.AddAttrs(h.traceAttrs(sc)...)
+
+	return h.next.Handle(ctx, r)
+}
+
+// spanKey identifies the trace correlation attributes of a span
+type spanKey struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+	flags   trace.TraceFlags
+}
+
+// traceAttrs returns the trace correlation attributes of a span context
+func (h *Handler) traceAttrs(sc trace.SpanContext) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3)
+
+	if h.opts.TraceIDKey != "" {
+		attrs = append(attrs, slog.String(h.opts.TraceIDKey, sc.TraceID().String()))
+	}
+
+	if h.opts.SpanIDKey != "" {
+		attrs = append(attrs, slog.String(h.opts.SpanIDKey, sc.SpanID().String()))
+	}
+
+	if h.opts.TraceFlagsKey != "" {
+		attrs = append(attrs, slog.String(h.opts.TraceFlagsKey, sc.TraceFlags().String()))
+	}
+
+	return attrs
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := &Handler{next: h.next.WithAttrs(attrs), opts: h.opts, groups: h.groups, attrs: h.attrs, chain: h.chain.WithAttrs(attrs)}
+
+	if h.opts.SpanEvents {
+		h2.attrs = h.attrs[:len(h.attrs):len(h.attrs)]
+
+		for _, a := range attrs {
+			h2.attrs = h.appendKeyValues(h2.attrs, h.groups, a)
+		}
+	}
+
+	return h2
+}
+
+// WithGroup implements [slog.Handler]
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &Handler{
+		next:   h.next.WithGroup(name),
+		opts:   h.opts,
+		groups: append(h.groups[:len(h.groups):len(h.groups)], name),
+		attrs:  h.attrs,
+		chain:  h.chain.WithGroup(h.next, name),
+	}
+}
+
+// eventAttrs converts a record into redacted and scrubbed span event attributes
+func (h *Handler) eventAttrs(r slog.Record) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(h.attrs)+r.NumAttrs()+2)
+	msg := h.opts.Sanitize(nil, slog.String(slog.MessageKey, r.Message))
+
+	kvs = append(kvs,
+		attribute.String("log.severity", r.Level.String()),
+		attribute.String("log.message", msg.Value.String()),
+	)
+	kvs = append(kvs, h.attrs...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		kvs = h.appendKeyValues(kvs, h.groups, a)
+
+		return true
+	})
+
+	return kvs
+}
+
+// appendKeyValues converts an attribute, flattening groups into dotted keys
+func (h *Handler) appendKeyValues(kvs []attribute.KeyValue, groups []string, a slog.Attr) []attribute.KeyValue {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+
+		for _, ga := range a.Value.Group() {
+			kvs = h.appendKeyValues(kvs, groups, ga)
+		}
+
+		return kvs
+	}
+
+	a = h.opts.Sanitize(groups, a)
+	if a.Key == "" {
+		return kvs
+	}
+
+	key := a.Key
+	if len(groups) != 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(kvs, attribute.String(key, a.Value.String()))
+	case slog.KindInt64:
+		return append(kvs, attribute.Int64(key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(kvs, attribute.Int64(key, int64(min(a.Value.Uint64(), math.MaxInt64))))
+	case slog.KindFloat64:
+		return append(kvs, attribute.Float64(key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(kvs, attribute.Bool(key, a.Value.Bool()))
+	}
+
+	return append(kvs, attribute.String(key, fmt.Sprint(a.Value.Any())))
+}
+
+// WithKeys sets the attribute keys for the trace ID, span ID and trace flags.
+// An empty key omits the corresponding attribute.
+// traceID: Key for the trace ID
+// spanID: Key for the span ID
+// traceFlags: Key for the trace flags
+// Returns: Configuration option function
+func WithKeys(traceID, spanID, traceFlags string) Option {
+	return func(o *Options) {
+		o.TraceIDKey = traceID
+		o.SpanIDKey = spanID
+		o.TraceFlagsKey = traceFlags
+	}
+}
+
+// WithSpanEvents enables/disables recording logs as events on the active span
+// enabled: Whether to record span events
+// Returns: Configuration option function
+func WithSpanEvents(enabled bool) Option {
+	return func(o *Options) {
+		o.SpanEvents = enabled
+	}
+}
+
+// WithEventLevel sets the minimum level of records added as span events
+// level: Minimum level, Error by default
+// Returns: Configuration option function
+func WithEventLevel(level logging.Level) Option {
+	return func(o *Options) {
+		o.EventLevel = level
+	}
+}
+
+// WithSanitizer sets the redaction and scrubbing of span event attributes.
+// Pass the redaction and scrubbing options of the logger to [logging.Sanitizer]
+// so that span events hide the same values as the log output.
+// sanitize: ReplaceAttr-like function such as logging.Sanitizer(logging.WithScrub(logging.ScrubAll))
+// Returns: Configuration option function
+func WithSanitizer(sanitize func(groups []string, a slog.Attr) slog.Attr) Option {
+	return func(o *Options) {
+		o.Sanitize = sanitize
+	}
+}
@@ -0,0 +1,324 @@
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		level      slog.Level
+		wantKeys   []string
+		absentKeys []string
+		wantEvents int
+	}{
+		{
+			"default keys",
+			nil,
+			slog.LevelInfo,
+			[]string{"trace_id=", "span_id=", "trace_flags=01"},
+			nil,
+			0,
+		},
+		{
+			"custom keys",
+			[]Option{WithKeys("traceId", "spanId", "")},
+			slog.LevelInfo,
+			[]string{"traceId=", "spanId="},
+			[]string{"trace_flags"},
+			0,
+		},
+		{
+			"error recorded as span event",
+			[]Option{WithSpanEvents(true)},
+			slog.LevelError,
+			[]string{"trace_id="},
+			nil,
+			1,
+		},
+		{
+			"info not recorded as span event",
+			[]Option{WithSpanEvents(true)},
+			slog.LevelInfo,
+			[]string{"trace_id="},
+			nil,
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+
+			var buf bytes.Buffer
+
+			logger := slog.New(NewHandler(slog.NewTextHandler(&buf, nil), tt.opts...))
+			logger.Log(ctx, tt.level, "msg", "user", "alice")
+
+			span.End()
+
+			out := buf.String()
+
+			for _, key := range tt.wantKeys {
+				if !strings.Contains(out, key) {
+					t.Errorf("output = %q, want it to contain %q", out, key)
+				}
+			}
+
+			for _, key := range tt.absentKeys {
+				if strings.Contains(out, key) {
+					t.Errorf("output = %q, want it not to contain %q", out, key)
+				}
+			}
+
+			if !strings.Contains(out, span.SpanContext().TraceID().String()) {
+				t.Errorf("output = %q, want trace ID %s", out, span.SpanContext().TraceID())
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(spans))
+			}
+
+			if got := len(spans[0].Events()); got != tt.wantEvents {
+				t.Errorf("span events = %d, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestHandlerNoSpan(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buf, nil)))
+	logger.InfoContext(context.Background(), "msg")
+
+	if strings.Contains(buf.String(), "trace_id") {
+		t.Errorf("output = %q, want no trace attributes without a span", buf.String())
+	}
+}
+
+func TestSpanEventAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+
+	logger := slog.New(NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), WithSpanEvents(true)))
+	logger.ErrorContext(ctx, "failed", "attempt", 3, slog.Group("req", "method", "GET"))
+
+	span.End()
+
+	event := recorder.Ended()[0].Events()[0]
+
+	got := make(map[string]string, len(event.Attributes))
+	for _, kv := range event.Attributes {
+		got[string(kv.Key)] = kv.Value.Emit()
+	}
+
+	want := map[string]string{
+		"log.severity": "ERROR",
+		"log.message":  "failed",
+		"attempt":      "3",
+		"req.method":   "GET",
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("event attribute %s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestSpanEventSanitized(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		log    func(l *slog.Logger, ctx context.Context)
+		want   map[string]string
+		absent []string
+	}{
+		{
+			"default redaction",
+			nil,
+			func(l *slog.Logger, ctx context.Context) {
+				l.ErrorContext(ctx, "login failed", "password", "hunter2", slog.Group("auth", "token", "abc"))
+			},
+			map[string]string{"password": "[REDACTED]", "auth.token": "[REDACTED]"},
+			[]string{"hunter2", "abc"},
+		},
+		{
+			"logger sanitizer",
+			[]Option{WithSanitizer(logging.Sanitizer(logging.WithScrub(logging.ScrubEmail), logging.WithRedactKeys("pin")))},
+			func(l *slog.Logger, ctx context.Context) {
+				l.ErrorContext(ctx, "mail to alice@example.com failed", "to", "alice@example.com", "pin", 1234)
+			},
+			map[string]string{"log.message": "mail to a****@example.com failed", "to": "a****@example.com", "pin": "[REDACTED]"},
+			[]string{"alice@", "1234"},
+		},
+		{
+			"dropped attributes",
+			[]Option{WithSanitizer(logging.Sanitizer(logging.WithRedactAction(logging.RedactActionDrop)))},
+			func(l *slog.Logger, ctx context.Context) {
+				l.ErrorContext(ctx, "failed", "secret", "s3cr3t", "n", 1)
+			},
+			map[string]string{"n": "1"},
+			[]string{"s3cr3t", "secret"},
+		},
+		{
+			"with attrs",
+			nil,
+			func(l *slog.Logger, ctx context.Context) {
+				l.With("svc", "api", "secret", "s3cr3t").WithGroup("db").With("table", "users").ErrorContext(ctx, "failed", "n", 1)
+			},
+			map[string]string{"svc": "api", "secret": "[REDACTED]", "db.table": "users", "db.n": "1"},
+			[]string{"s3cr3t"},
+		},
+		{
+			"groups and large unsigned values",
+			nil,
+			func(l *slog.Logger, ctx context.Context) {
+				l.WithGroup("db").ErrorContext(ctx, "failed", slog.Uint64("rows", math.MaxUint64))
+			},
+			map[string]string{"db.rows": strconv.FormatInt(math.MaxInt64, 10)},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+
+			opts := append([]Option{WithSpanEvents(true)}, tt.opts...)
+			tt.log(slog.New(NewHandler(slog.NewTextHandler(&bytes.Buffer{}, nil), opts...)), ctx)
+
+			span.End()
+
+			got := make(map[string]string)
+			for _, kv := range recorder.Ended()[0].Events()[0].Attributes {
+				got[string(kv.Key)] = kv.Value.Emit()
+			}
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("event attribute %s = %q, want %q", k, got[k], v)
+				}
+			}
+
+			for _, s := range tt.absent {
+				for k, v := range got {
+					if strings.Contains(k, s) || strings.Contains(v, s) {
+						t.Errorf("event attribute %s = %q, want no %q", k, v, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerGroups(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+
+	defer span.End()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}), WithKeys("trace_id", "", "")))
+
+	logger.With("svc", "api").WithGroup("db").With("table", "users").InfoContext(ctx, "query", "rows", 1)
+
+	want := "msg=query svc=api trace_id=" + span.SpanContext().TraceID().String() + " db.table=users db.rows=1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerGroupsSpans(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}), WithKeys("", "span_id", ""))).WithGroup("db")
+
+	ctxA, spanA := provider.Tracer("test").Start(context.Background(), "a")
+	ctxB, spanB := provider.Tracer("test").Start(context.Background(), "b")
+
+	defer spanA.End()
+	defer spanB.End()
+
+	logger.InfoContext(ctxA, "query", "n", 1)
+	logger.InfoContext(ctxB, "query", "n", 2)
+	logger.InfoContext(ctxA, "query", "n", 3)
+
+	want := "msg=query span_id=" + spanA.SpanContext().SpanID().String() + " db.n=1\n" +
+		"msg=query span_id=" + spanB.SpanContext().SpanID().String() + " db.n=2\n" +
+		"msg=query span_id=" + spanA.SpanContext().SpanID().String() + " db.n=3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSlogConformance(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewHandler(slog.NewJSONHandler(&buf, nil), WithSpanEvents(true))
+
+	err := slogtest.TestHandler(h, func() []map[string]any {
+		var records []map[string]any
+
+		for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+			if len(line) == 0 {
+				continue
+			}
+
+			var m map[string]any
+			if err := json.Unmarshal(line, &m); err != nil {
+				t.Fatalf("decode %q: %v", line, err)
+			}
+
+			records = append(records, m)
+		}
+
+		return records
+	})
+	if err != nil {
+		t.Errorf("slogtest: %v", err)
+	}
+}
@@ -0,0 +1,415 @@
+package logging
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// Default rate limiting configuration constants
+	defaultRateLimit    = 100.0
+	defaultRateBurst    = 100
+	defaultRateMaxKeys  = 10000
+	defaultRateMaxLevel = LevelError
+
+	// RateLimitedKey is the attribute key of the number of records dropped for a key,
+	// added to the next record allowed for that key
+	RateLimitedKey = "rate_limited"
+
+	// rateOverflowKey is the bucket shared by new keys when MaxKeys buckets are busy
+	rateOverflowKey = "\x00overflow"
+)
+
+// RateLimitOptions contains configuration for the rate limiting handler
+type RateLimitOptions struct {
+	Rate     float64 // Records per second allowed for each key
+	Burst    int     // Records allowed at once for each key
+	KeyAttr  string  // Attribute whose value identifies the bucket of a record, empty for one bucket
+	CallSite bool    // Whether the call site identifies the bucket of a record instead of KeyAttr
+	MaxKeys  int     // Maximum number of buckets tracked at once
+	MaxLevel Level   // Highest limited level, records above it are always logged
+}
+
+// RateLimitOption defines a function type for configuring RateLimitOptions
+type RateLimitOption func(*RateLimitOptions)
+
+// newRateLimitOptions applies the options on top of the default configuration
+func newRateLimitOptions(opts ...RateLimitOption) *RateLimitOptions {
+	config := &RateLimitOptions{
+		Rate:     defaultRateLimit,
+		Burst:    defaultRateBurst,
+		MaxKeys:  defaultRateMaxKeys,
+		MaxLevel: defaultRateMaxLevel,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// RateLimitStats contains counters of a rate limiting handler
+type RateLimitStats struct {
+	Allowed      uint64            // Records up to MaxLevel passed to the next handler
+	Limited      uint64            // Records up to MaxLevel dropped
+	LimitedByKey map[string]uint64 // Records dropped per tracked key (call sites as "dir/file.go:line")
+}
+
+// tokenBucket limits the records of one key
+type tokenBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped int    // records dropped since the last allowed one
+	limited uint64 // records dropped in total
+	key     string // key of the bucket in rateLimiter.buckets
+	label   string // key reported in stats
+}
+
+// rateLimiter holds the buckets shared by a RateLimitHandler and the handlers derived from it
+type rateLimiter struct {
+	opts *RateLimitOptions
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element // elements of lru holding *tokenBucket values
+	lru     *list.List               // buckets from the most to the least recently used
+	evicted map[string]uint64        // records dropped per label by evicted buckets
+	allowed uint64
+	limited uint64
+}
+
+// RateLimitHandler is a [slog.Handler] middleware limiting records with token buckets.
+// Each key, such as a tenant ID, a client IP or a call site, gets a bucket of
+// [RateLimitOptions.Burst] records refilled at [RateLimitOptions.Rate] records per
+// second, so that one noisy key cannot drown the records of the others.
+//
+// The key is the value of the [RateLimitOptions.KeyAttr] attribute, looked up in the
+// record, the attributes added by With and the context attributes; records without it
+// share one bucket. The first record allowed after drops carries the [RateLimitedKey]
+// attribute with the number of dropped records. Fatal records are never limited.
+type RateLimitHandler struct {
+	next    slog.Handler
+	limiter *rateLimiter
+	key     slog.Value // value of KeyAttr added by WithAttrs
+	hasKey  bool
+	grouped bool // whether WithGroup was called, hiding record attributes from KeyAttr
+}
+
+// NewRateLimitHandler wraps a handler with per-key rate limiting
+// next: Handler receiving the allowed records
+// opts: Variadic list of configuration options
+// Returns: Rate limiting handler
+func NewRateLimitHandler(next slog.Handler, opts ...RateLimitOption) *RateLimitHandler {
+	return &RateLimitHandler{
+		next: next,
+		limiter: &rateLimiter{
+			opts:    newRateLimitOptions(opts...),
+			buckets: make(map[string]*list.Element),
+			lru:     list.New(),
+			evicted: make(map[string]uint64),
+		},
+	}
+}
+
+// RateLimitMiddleware returns a middleware limiting records, for use with [WithMiddleware]
+// opts: Variadic list of configuration options
+// Returns: Rate limiting middleware
+func RateLimitMiddleware(opts ...RateLimitOption) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewRateLimitHandler(next, opts...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *RateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *RateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level > h.limiter.opts.MaxLevel {
+		return h.next.Handle(ctx, r)
+	}
+
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	allowed, dropped := h.limiter.allow(h.bucketKey(ctx, r), r.PC, now)
+	if !allowed {
+		return nil
+	}
+
+	if dropped > 0 {
+		r = r.Clone()
+		r.AddAttrs(Int(RateLimitedKey, dropped))
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *RateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+
+	if keyAttr := h.limiter.opts.KeyAttr; keyAttr != "" && !h.grouped {
+		for _, a := range attrs {
+			if a.Key == keyAttr {
+				h2.key, h2.hasKey = a.Value, true
+			}
+		}
+	}
+
+	return &h2
+}
+
+// WithGroup implements [slog.Handler]
+func (h *RateLimitHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.grouped = true
+
+	return &h2
+}
+
+// Stats returns the counters of the handler and of the handlers derived from it
+func (h *RateLimitHandler) Stats() RateLimitStats {
+	l := h.limiter
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := RateLimitStats{
+		Allowed:      l.allowed,
+		Limited:      l.limited,
+		LimitedByKey: maps.Clone(l.evicted),
+	}
+
+	for e := l.lru.Front(); e != nil; e = e.Next() {
+		if b := e.Value.(*tokenBucket); b.limited > 0 {
+			stats.LimitedByKey[b.label] += b.limited
+		}
+	}
+
+	return stats
+}
+
+// bucketKey returns the key of the bucket limiting the record
+func (h *RateLimitHandler) bucketKey(ctx context.Context, r slog.Record) string {
+	opts := h.limiter.opts
+
+	if opts.CallSite {
+		return fmt.Sprintf("pc:%x", r.PC)
+	}
+
+	if opts.KeyAttr == "" {
+		return ""
+	}
+
+	var (
+		key   slog.Value
+		found bool
+	)
+
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == opts.KeyAttr {
+				key, found = a.Value, true
+			}
+
+			return true
+		})
+	}
+
+	if !found && h.hasKey {
+		key, found = h.key, true
+	}
+
+	if !found {
+		for _, a := range AttrsFromContext(ctx) {
+			if a.Key == opts.KeyAttr {
+				key, found = a.Value, true
+			}
+		}
+	}
+
+	if !found {
+		return ""
+	}
+
+	return opts.KeyAttr + "=" + key.Resolve().String()
+}
+
+// allow takes a token from the bucket of the key and reports whether the record is
+// allowed, along with the number of records of the key dropped since the last allowed one
+func (l *rateLimiter) allow(key string, pc uintptr, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(key, pc, now)
+
+	// records may arrive out of order, e.g. backfilled or from concurrent goroutines,
+	// so the bucket clock only moves forward and an interval is only credited once
+	if now.After(b.last) {
+		b.tokens = min(float64(l.opts.Burst), b.tokens+now.Sub(b.last).Seconds()*l.opts.Rate)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		b.dropped++
+		b.limited++
+		l.limited++
+
+		return false, 0
+	}
+
+	b.tokens--
+	l.allowed++
+
+	dropped := b.dropped
+	b.dropped = 0
+
+	return true, dropped
+}
+
+// bucket returns the bucket of the key, creating it full. When MaxKeys buckets are
+// tracked, the least recently used buckets are evicted while they are refilled, their
+// dropped records being kept in the stats; if none is, new keys share an overflow bucket.
+func (l *rateLimiter) bucket(key string, pc uintptr, now time.Time) *tokenBucket {
+	if e, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(e)
+
+		return e.Value.(*tokenBucket)
+	}
+
+	for l.lru.Len() >= l.opts.MaxKeys {
+		if !l.evict(now) {
+			break
+		}
+	}
+
+	if l.lru.Len() >= l.opts.MaxKeys {
+		key = rateOverflowKey
+		if e, ok := l.buckets[key]; ok {
+			l.lru.MoveToFront(e)
+
+			return e.Value.(*tokenBucket)
+		}
+	}
+
+	b := &tokenBucket{tokens: float64(l.opts.Burst), last: now, key: key, label: key}
+
+	if l.opts.CallSite && key != rateOverflowKey {
+		b.label = callSite(pc)
+	}
+
+	l.buckets[key] = l.lru.PushFront(b)
+
+	return b
+}
+
+// evict removes the least recently used bucket if it is refilled, reporting whether it did.
+// Its dropped records are added to the evicted counts, under the overflow label once
+// MaxKeys labels are kept there.
+func (l *rateLimiter) evict(now time.Time) bool {
+	e := l.lru.Back()
+	if e == nil {
+		return false
+	}
+
+	b := e.Value.(*tokenBucket)
+
+	tokens := b.tokens
+	if now.After(b.last) {
+		tokens += now.Sub(b.last).Seconds() * l.opts.Rate
+	}
+
+	if tokens < float64(l.opts.Burst) {
+		return false
+	}
+
+	if b.limited > 0 {
+		label := b.label
+		if _, ok := l.evicted[label]; !ok && len(l.evicted) >= l.opts.MaxKeys {
+			label = rateOverflowKey
+		}
+
+		l.evicted[label] += b.limited
+	}
+
+	l.lru.Remove(e)
+	delete(l.buckets, b.key)
+
+	return true
+}
+
+// callSite formats the source location of a program counter as "dir/file.go:line"
+func callSite(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File)), frame.Line)
+}
+
+// WithRateLimit sets the rate and burst of each bucket
+// perSecond: Records per second allowed for each key
+// burst: Records allowed at once for each key
+// Returns: Rate limiting option function
+func WithRateLimit(perSecond float64, burst int) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.Rate = perSecond
+		o.Burst = burst
+	}
+}
+
+// WithRateLimitKey sets the attribute whose value identifies the bucket of a record
+// key: Attribute key such as "tenant_id" or "client_ip"
+// Returns: Rate limiting option function
+func WithRateLimitKey(key string) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.KeyAttr = key
+		o.CallSite = false
+	}
+}
+
+// WithRateLimitCallSite identifies the bucket of a record by its call site,
+// so that one noisy logging statement cannot drown the others
+// Returns: Rate limiting option function
+func WithRateLimitCallSite() RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.CallSite = true
+	}
+}
+
+// WithRateLimitMaxKeys sets the maximum number of buckets tracked at once
+// maxKeys: Maximum number of buckets
+// Returns: Rate limiting option function
+func WithRateLimitMaxKeys(maxKeys int) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.MaxKeys = maxKeys
+	}
+}
+
+// WithRateLimitMaxLevel sets the highest limited level; records above it are always logged
+// level: Highest limited level, Error by default
+// Returns: Rate limiting option function
+func WithRateLimitMaxLevel(level Level) RateLimitOption {
+	return func(o *RateLimitOptions) {
+		o.MaxLevel = level
+	}
+}
@@ -0,0 +1,207 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    []RateLimitOption
+		level   Level
+		count   int
+		spacing time.Duration
+		want    int
+	}{
+		{"burst", []RateLimitOption{WithRateLimit(1, 3)}, LevelInfo, 10, 0, 3},
+		{"refill", []RateLimitOption{WithRateLimit(10, 1)}, LevelInfo, 10, 50 * time.Millisecond, 5},
+		{"under rate", []RateLimitOption{WithRateLimit(10, 1)}, LevelInfo, 10, 100 * time.Millisecond, 10},
+		{"error limited by default", []RateLimitOption{WithRateLimit(1, 1)}, LevelError, 5, 0, 1},
+		{"fatal never limited", []RateLimitOption{WithRateLimit(1, 1)}, LevelFatal, 5, 0, 5},
+		{"max level", []RateLimitOption{WithRateLimit(1, 1), WithRateLimitMaxLevel(LevelWarn)}, LevelError, 5, 0, 5},
+		{"out of order", []RateLimitOption{WithRateLimit(10, 3)}, LevelInfo, 5, -time.Second, 3},
+		{"zero burst", []RateLimitOption{WithRateLimit(1, 0)}, LevelInfo, 5, time.Second, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			h := NewRateLimitHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelDebug}), tt.opts...)
+
+			for i := range tt.count {
+				r := slog.NewRecord(start.Add(time.Duration(i)*tt.spacing), tt.level, "limited", 0)
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := strings.Count(buf.String(), "msg=limited"); got != tt.want {
+				t.Errorf("logged %d records, want %d:\n%s", got, tt.want, buf.String())
+			}
+
+			if stats := h.Stats(); stats.Limited != uint64(tt.count-tt.want) {
+				t.Errorf("stats = %+v, want %d limited", stats, tt.count-tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitHandlerKeys(t *testing.T) {
+	ctxA := ContextWithAttrs(context.Background(), String("tenant_id", "a"))
+
+	var buf bytes.Buffer
+
+	h := NewRateLimitHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithRateLimit(0, 1), WithRateLimitKey("tenant_id"))
+	logger := slog.New(h)
+
+	logger.Info("a", "tenant_id", "a")
+	logger.InfoContext(ctxA, "a")           // key from the context
+	logger.With("tenant_id", "a").Info("a") // key from With
+	logger.Info("b", "tenant_id", "b")      // other tenants keep their bucket
+	logger.Info("none")                     // records without the key share a bucket
+	logger.Info("none")
+	logger.WithGroup("g").Info("c", "tenant_id", "c") // grouped attributes are not the key
+
+	want := "msg=a tenant_id=a\nmsg=b tenant_id=b\nmsg=none\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	stats := h.Stats()
+	wantByKey := map[string]uint64{"tenant_id=a": 2, "": 2}
+
+	if stats.Limited != 4 || len(stats.LimitedByKey) != len(wantByKey) {
+		t.Fatalf("stats = %+v, want %v", stats, wantByKey)
+	}
+
+	for key, n := range wantByKey {
+		if stats.LimitedByKey[key] != n {
+			t.Errorf("limited[%q] = %d, want %d", key, stats.LimitedByKey[key], n)
+		}
+	}
+}
+
+func TestRateLimitHandlerCallSite(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewRateLimitHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithRateLimit(0, 1), WithRateLimitCallSite())
+	logger := slog.New(h)
+
+	for range 3 {
+		logger.Info("loop")
+	}
+
+	logger.Info("other")
+
+	if got, want := buf.String(), "msg=loop\nmsg=other\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	limited := h.Stats().LimitedByKey
+	if len(limited) != 1 {
+		t.Fatalf("limited = %v, want one call site", limited)
+	}
+
+	for site, n := range limited {
+		if !strings.Contains(site, "/ratelimit_test.go:") || n != 2 {
+			t.Errorf("limited[%q] = %d, want a ratelimit_test.go call site with 2", site, n)
+		}
+	}
+}
+
+func TestRateLimitHandlerDroppedCount(t *testing.T) {
+	var buf bytes.Buffer
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewRateLimitHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithRateLimit(1, 1))
+
+	for _, offset := range []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, time.Second, 2 * time.Second} {
+		_ = h.Handle(context.Background(), slog.NewRecord(start.Add(offset), LevelInfo, "tick", 0))
+	}
+
+	if got, want := buf.String(), "msg=tick\nmsg=tick rate_limited=2\nmsg=tick\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRateLimitHandlerMaxKeys(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewRateLimitHandler(slog.NewTextHandler(io.Discard, nil),
+		WithRateLimit(1, 1), WithRateLimitKey("ip"), WithRateLimitMaxKeys(2))
+
+	handle := func(offset time.Duration, ip string) {
+		r := slog.NewRecord(start.Add(offset), LevelInfo, "request", 0)
+		r.AddAttrs(String("ip", ip))
+		_ = h.Handle(context.Background(), r)
+	}
+
+	handle(0, "1")
+	handle(0, "2")
+	handle(0, "3")           // both buckets are busy: the overflow bucket is used
+	handle(0, "4")           // and is shared by new keys
+	handle(time.Minute, "5") // idle buckets are evicted
+
+	if got := len(h.limiter.buckets); got != 2 {
+		t.Errorf("tracked %d buckets, want 2", got)
+	}
+
+	if stats := h.Stats(); stats.Allowed != 4 || stats.Limited != 1 {
+		t.Errorf("stats = %+v, want 4 allowed and 1 limited", stats)
+	}
+}
+
+func TestRateLimitHandlerEviction(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewRateLimitHandler(slog.NewTextHandler(io.Discard, nil),
+		WithRateLimit(1, 1), WithRateLimitKey("ip"), WithRateLimitMaxKeys(2))
+
+	handle := func(offset time.Duration, ip string) {
+		r := slog.NewRecord(start.Add(offset), LevelInfo, "request", 0)
+		r.AddAttrs(String("ip", ip))
+		_ = h.Handle(context.Background(), r)
+	}
+
+	handle(0, "1")
+	handle(0, "1") // limited
+	handle(0, "2")
+	handle(0, "2") // limited
+	handle(time.Minute, "1")
+	handle(time.Minute, "3") // evicts the least recently used bucket, of ip 2
+
+	if _, ok := h.limiter.buckets["ip=2"]; ok {
+		t.Errorf("bucket of ip 2 tracked, want it evicted")
+	}
+
+	if _, ok := h.limiter.buckets["ip=1"]; !ok {
+		t.Errorf("bucket of ip 1 evicted, want it tracked")
+	}
+
+	want := map[string]uint64{"ip=1": 1, "ip=2": 1}
+	if got := h.Stats().LimitedByKey; !maps.Equal(got, want) {
+		t.Errorf("LimitedByKey = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkRateLimitHandler(b *testing.B) {
+	logger := slog.New(NewRateLimitHandler(slog.NewTextHandler(io.Discard, nil), WithRateLimitKey("tenant_id")))
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("request handled", "tenant_id", "acme", "status", 200)
+		}
+	})
+}
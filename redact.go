@@ -0,0 +1,252 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+)
+
+// RedactAction defines how the value of a redacted attribute is rendered
+type RedactAction int
+
+const (
+	// RedactActionMask replaces the value with the configured mask
+	RedactActionMask RedactAction = iota
+
+	// RedactActionHash replaces the value with a truncated HMAC-SHA-256 keyed with
+	// [Options.RedactHashKey], which keeps equal values correlatable without exposing
+	// them to dictionary attacks. Without a key, a random key is generated per process.
+	RedactActionHash
+
+	// RedactActionDrop removes the attribute from the record
+	RedactActionDrop
+)
+
+const (
+	// defaultRedactAction is the default action for redacted attributes
+	defaultRedactAction = RedactActionMask
+
+	// defaultRedactMask is the default replacement for redacted values
+	defaultRedactMask = redactedValue
+
+	// redactHashLen is the number of hex characters kept from the digest
+	redactHashLen = 16
+
+	// redactHashKeyLen is the length of the random per-process HMAC key
+	redactHashKeyLen = 32
+)
+
+// processRedactHashKey returns the random HMAC key used when no key is configured
+var processRedactHashKey = sync.OnceValue(func() []byte {
+	key := make([]byte, redactHashKeyLen)
+	_, _ = rand.Read(key)
+
+	return key
+})
+
+// defaultRedactKeys is the secure default list of redacted key patterns.
+// Token patterns are anchored at the end so that "token", "access_token" and
+// "authToken" match while counters such as "max_tokens" or "token_count" do not.
+var defaultRedactKeys = []string{
+	"*password*",
+	"*secret*",
+	"*token",
+	"*authorization",
+	"*cookie*",
+}
+
+// DefaultRedactKeys returns a copy of the default list of redacted key patterns.
+// Use it to extend the defaults with [WithRedactKeys].
+func DefaultRedactKeys() []string {
+	return append([]string(nil), defaultRedactKeys...)
+}
+
+// redactor matches attribute keys against the configured patterns
+type redactor struct {
+	exact    map[string]struct{}
+	patterns []keyPattern
+}
+
+// keyPatternKind selects how a key pattern is matched
+type keyPatternKind int
+
+const (
+	patternGlob     keyPatternKind = iota // matched with path.Match
+	patternContains                       // "*part*"
+	patternPrefix                         // "prefix*"
+	patternSuffix                         // "*suffix"
+)
+
+// keyPattern is a lower-cased glob pattern with a precomputed fast path
+type keyPattern struct {
+	kind    keyPatternKind
+	pattern string
+	inner   string
+}
+
+// newRedactor prepares lower-cased key names and glob patterns for matching
+func newRedactor(keys []string) *redactor {
+	r := &redactor{
+		exact: make(map[string]struct{}, len(keys)),
+	}
+
+	for _, key := range keys {
+		key = strings.ToLower(key)
+
+		if strings.ContainsAny(key, "*?[\\") {
+			r.patterns = append(r.patterns, newKeyPattern(key))
+		} else {
+			r.exact[key] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+// newKeyPattern classifies a glob pattern, avoiding path.Match for the
+// common "*part*", "prefix*" and "*suffix" forms
+func newKeyPattern(pattern string) keyPattern {
+	p := keyPattern{
+		kind:    patternGlob,
+		pattern: pattern,
+		inner:   strings.Trim(pattern, "*"),
+	}
+
+	if strings.ContainsAny(p.inner, "*?[\\") || p.inner == "" {
+		return p
+	}
+
+	switch {
+	case strings.HasPrefix(pattern, "*") && strings.HasSuffix(pattern, "*"):
+		p.kind = patternContains
+	case strings.HasPrefix(pattern, "*"):
+		p.kind = patternSuffix
+	case strings.HasSuffix(pattern, "*"):
+		p.kind = patternPrefix
+	}
+
+	return p
+}
+
+// match reports whether the lower-cased key matches the pattern
+func (p keyPattern) match(key string) bool {
+	switch p.kind {
+	case patternContains:
+		return strings.Contains(key, p.inner)
+	case patternPrefix:
+		return strings.HasPrefix(key, p.inner)
+	case patternSuffix:
+		return strings.HasSuffix(key, p.inner)
+	}
+
+	ok, _ := path.Match(p.pattern, key)
+
+	return ok
+}
+
+// match reports whether the key matches any name or pattern, ignoring case
+func (r *redactor) match(key string) bool {
+	if r == nil || len(r.exact) == 0 && len(r.patterns) == 0 {
+		return false
+	}
+
+	key = strings.ToLower(key)
+
+	if _, ok := r.exact[key]; ok {
+		return true
+	}
+
+	for _, p := range r.patterns {
+		if p.match(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchAny reports whether any of the keys matches, such as the keys of enclosing groups
+func (r *redactor) matchAny(keys []string) bool {
+	for _, key := range keys {
+		if r.match(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactAttr applies the configured redaction action to an attribute
+func (o *Options) redactAttr(a slog.Attr) slog.Attr {
+	switch o.RedactAction {
+	case RedactActionDrop:
+		return slog.Attr{}
+	case RedactActionHash:
+		key := o.RedactHashKey
+		if len(key) == 0 {
+			key = processRedactHashKey()
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(a.Value.Resolve().String()))
+
+		return slog.String(a.Key, "hmac:"+hex.EncodeToString(mac.Sum(nil))[:redactHashLen])
+	}
+
+	return slog.String(a.Key, o.RedactMask)
+}
+
+// isBuiltinKey reports whether the attribute is one of the top-level keys added by slog
+func isBuiltinKey(groups []string, key string) bool {
+	if len(groups) != 0 {
+		return false
+	}
+
+	_, ok := defaultReplaceAttrs[key]
+
+	return ok
+}
+
+// WithRedactKeys sets the key names or glob patterns whose values are redacted.
+// Matching is case-insensitive and applies at any group depth.
+// The list replaces [DefaultRedactKeys]; pass no keys to disable redaction.
+// keys: Key names or path.Match patterns such as "*_token"
+// Returns: Configuration option function
+func WithRedactKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.RedactKeys = keys
+	}
+}
+
+// WithRedactAction sets how redacted values are rendered
+// action: One of [RedactActionMask], [RedactActionHash] or [RedactActionDrop]
+// Returns: Configuration option function
+func WithRedactAction(action RedactAction) Option {
+	return func(o *Options) {
+		o.RedactAction = action
+	}
+}
+
+// WithRedactHashKey sets the HMAC key of [RedactActionHash]. Processes sharing the key
+// log equal values with equal hashes; keep it as secret as the values themselves.
+// key: HMAC key, a random per-process key when empty
+// Returns: Configuration option function
+func WithRedactHashKey(key []byte) Option {
+	return func(o *Options) {
+		o.RedactHashKey = key
+	}
+}
+
+// WithRedactMask sets the replacement used by [RedactActionMask]
+// mask: Replacement value
+// Returns: Configuration option function
+func WithRedactMask(mask string) Option {
+	return func(o *Options) {
+		o.RedactMask = mask
+	}
+}
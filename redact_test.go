@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		groups []string
+		attr   slog.Attr
+		want   slog.Attr
+	}{
+		{"default exact key", nil, nil, slog.String("Authorization", "Bearer x"), slog.String("Authorization", "[REDACTED]")},
+		{"default glob key", nil, nil, slog.String("user_password", "qwerty"), slog.String("user_password", "[REDACTED]")},
+		{"default header suffix", nil, nil, slog.String("Proxy-Authorization", "Basic x"), slog.String("Proxy-Authorization", "[REDACTED]")},
+		{"default cookie header", nil, nil, slog.String("Set-Cookie", "sid=1"), slog.String("Set-Cookie", "[REDACTED]")},
+		{"enclosing group key", nil, []string{"req", "password"}, slog.String("hint", "pet"), slog.String("hint", "[REDACTED]")},
+		{"nested group", nil, []string{"req", "headers"}, slog.String("cookie", "a=b"), slog.String("cookie", "[REDACTED]")},
+		{"default suffix key", nil, nil, slog.String("access_token", "abc"), slog.String("access_token", "[REDACTED]")},
+		{"default camel case suffix key", nil, nil, slog.String("authToken", "abc"), slog.String("authToken", "[REDACTED]")},
+		{"token count not redacted", nil, nil, slog.Int("max_tokens", 512), slog.Int("max_tokens", 512)},
+		{"token prefix not redacted", nil, nil, slog.Int("token_count", 42), slog.Int("token_count", 42)},
+		{"non-sensitive key", nil, nil, slog.String("user", "alice"), slog.String("user", "alice")},
+		{"builtin key", nil, nil, slog.String(slog.MessageKey, "token"), slog.String(slog.MessageKey, "token")},
+		{
+			"custom keys replace defaults",
+			[]Option{WithRedactKeys("ssn")},
+			nil,
+			slog.String("password", "qwerty"),
+			slog.String("password", "qwerty"),
+		},
+		{
+			"custom mask",
+			[]Option{WithRedactMask("***")},
+			nil,
+			slog.String("secret", "s"),
+			slog.String("secret", "***"),
+		},
+		{
+			"hash action",
+			[]Option{WithRedactAction(RedactActionHash), WithRedactHashKey([]byte("k"))},
+			nil,
+			slog.String("token", "hello"),
+			slog.String("token", "hmac:406e4b43f87095aa"),
+		},
+		{
+			"drop action",
+			[]Option{WithRedactAction(RedactActionDrop)},
+			nil,
+			slog.String("token", "hello"),
+			slog.Attr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newOptions(tt.opts...)
+
+			got := config.replaceAttr(tt.groups, tt.attr)
+			if got.Key != tt.want.Key || got.Value.String() != tt.want.Value.String() {
+				t.Errorf("replaceAttr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHashProcessKey(t *testing.T) {
+	config := newOptions(WithRedactAction(RedactActionHash))
+
+	first := config.replaceAttr(nil, slog.String("token", "hello")).Value.String()
+	second := config.replaceAttr(nil, slog.String("token", "hello")).Value.String()
+
+	if first != second {
+		t.Errorf("hashes = %q, %q, want equal values hashed equally", first, second)
+	}
+
+	// the unkeyed SHA-256 of "hello" can be found in any dictionary
+	if !strings.HasPrefix(first, "hmac:") || strings.Contains(first, "2cf24dba5fb0a30e") {
+		t.Errorf("hash = %q, want an HMAC with a random key", first)
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	type credentials struct {
+		User string
+		Hash string
+	}
+
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+		want string
+	}{
+		{
+			"group attribute",
+			func(l *Logger) { l.Info("msg", slog.Group("password", "old", "a", "new", "b")) },
+			"password.old=[REDACTED] password.new=[REDACTED]",
+		},
+		{
+			"object resolving to a group",
+			func(l *Logger) { l.Info("msg", Object("secret", credentials{User: "alice", Hash: "x"})) },
+			"secret.User=[REDACTED] secret.Hash=[REDACTED]",
+		},
+		{
+			"logger group",
+			func(l *Logger) { l.WithGroup("cookie").Info("msg", "sid", "1") },
+			"cookie.sid=[REDACTED]",
+		},
+		{
+			"other groups",
+			func(l *Logger) { l.Info("msg", slog.Group("user", "name", "alice")) },
+			"user.name=alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(NewLogger(WithWriter(&buf)))
+
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("output = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	// RequestIDKey is the attribute key used for request IDs in log records
+	RequestIDKey = "request_id"
+
+	// DefaultRequestIDHeader is the default HTTP header carrying request IDs
+	DefaultRequestIDHeader = "X-Request-ID"
+
+	// crockfordAlphabet is the base32 alphabet used by ULIDs
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// maxRequestIDLength is the maximum length of request IDs accepted by ValidRequestID
+	maxRequestIDLength = 128
+)
+
+// requestIDKey is a private type used as unique context key to store request IDs
+type requestIDKey struct{}
+
+// RequestIDOptions contains configuration for request ID propagation
+type RequestIDOptions struct {
+	Headers   []string          // HTTP headers read in order, the first one is also written
+	Generator func() string     // Function generating new IDs
+	Validator func(string) bool // Function accepting inbound IDs, nil accepts every non-empty ID
+}
+
+// RequestIDOption defines a function type for configuring RequestIDOptions
+type RequestIDOption func(*RequestIDOptions)
+
+// newRequestIDOptions applies the options on top of the default configuration
+func newRequestIDOptions(opts ...RequestIDOption) *RequestIDOptions {
+	config := &RequestIDOptions{
+		Headers:   []string{DefaultRequestIDHeader},
+		Generator: NewUUIDv7,
+		Validator: ValidRequestID,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// ContextWithRequestID stores a request ID in the context.
+// The ID is also added to the context attributes (see [ContextWithAttrs]) under
+// [RequestIDKey], so loggers created by [NewLogger] include it in every record.
+//
+// Parameters:
+//   - ctx: parent context
+//   - id: request ID
+//
+// Returns:
+//   - new context.Context containing the request ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	ctx = ContextWithAttrs(ctx, String(RequestIDKey, id))
+
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext extracts the request ID stored by [ContextWithRequestID].
+//
+// Parameters:
+//   - ctx: context containing the request ID
+//
+// Returns:
+//   - request ID or an empty string if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+// RequestIDFromHeader reads the request ID from the first configured header holding a valid one.
+// Inbound IDs are client-controlled, so IDs rejected by the validator (by default
+// [ValidRequestID]) are ignored rather than logged and propagated.
+// h: Incoming HTTP headers
+// opts: Header names, generator and validator options
+// Returns: Request ID or an empty string if no header holds a valid one
+func RequestIDFromHeader(h http.Header, opts ...RequestIDOption) string {
+	config := newRequestIDOptions(opts...)
+
+	for _, name := range config.Headers {
+		if id := h.Get(name); config.Valid(id) {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// Valid reports whether an inbound request ID is accepted
+// id: Request ID read from a header or metadata
+// Returns: Whether the ID is non-empty and accepted by the validator
+func (o *RequestIDOptions) Valid(id string) bool {
+	return id != "" && (o.Validator == nil || o.Validator(id))
+}
+
+// ValidRequestID is the default request ID validator. It accepts IDs of at most
+// 128 characters made of ASCII letters, digits, '.', '_' and '-', which covers
+// UUIDs, ULIDs and most tracing IDs while keeping control characters, separators
+// and oversized values out of logs and outgoing headers.
+// id: Request ID
+// Returns: Whether the ID is valid
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// EnsureRequestID reads the request ID of an incoming request or, when no header holds
+// a valid one, generates a new one, and stores it in the request context.
+// r: Incoming HTTP request
+// opts: Header names, generator and validator options
+// Returns: Context containing the request ID and the ID itself
+func EnsureRequestID(r *http.Request, opts ...RequestIDOption) (context.Context, string) {
+	config := newRequestIDOptions(opts...)
+
+	id := RequestIDFromHeader(r.Header, opts...)
+	if id == "" {
+		id = config.Generator()
+	}
+
+	return ContextWithRequestID(r.Context(), id), id
+}
+
+// SetRequestIDHeader writes the request ID from the context into outgoing headers
+// using the first configured header name.
+// ctx: Context containing the request ID
+// h: Outgoing HTTP headers
+// opts: Header names and generator options
+// Returns: Nothing; headers are left untouched if the context has no request ID
+func SetRequestIDHeader(ctx context.Context, h http.Header, opts ...RequestIDOption) {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return
+	}
+
+	if headers := newRequestIDOptions(opts...).Headers; len(headers) > 0 {
+		h.Set(headers[0], id)
+	}
+}
+
+// NewUUIDv7 generates a time-ordered RFC 9562 UUID version 7
+// Returns: UUID in the canonical 8-4-4-4-12 hex form
+func NewUUIDv7() string {
+	var b [16]byte
+
+	_, _ = rand.Read(b[6:])
+
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	var out [36]byte
+
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+
+	return string(out[:])
+}
+
+// NewULID generates a lexicographically sortable ULID
+// Returns: ULID as 26 Crockford base32 characters
+func NewULID() string {
+	var b [16]byte
+
+	_, _ = rand.Read(b[6:])
+
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var out [26]byte
+
+	// 128 bits are encoded as 26 groups of 5 bits, starting from the least significant
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out[:])
+}
+
+// WithRequestIDHeaders sets the HTTP headers that carry request IDs.
+// Headers are read in order; the first one is used when writing.
+// headers: Header names such as "X-Request-ID" or "X-Correlation-ID"
+// Returns: Request ID option function
+func WithRequestIDHeaders(headers ...string) RequestIDOption {
+	return func(o *RequestIDOptions) {
+		o.Headers = headers
+	}
+}
+
+// WithRequestIDValidator sets the function accepting inbound request IDs;
+// IDs it rejects are replaced by generated ones
+// valid: Validator such as [ValidRequestID] (default), or nil to accept every non-empty ID
+// Returns: Request ID option function
+func WithRequestIDValidator(valid func(id string) bool) RequestIDOption {
+	return func(o *RequestIDOptions) {
+		o.Validator = valid
+	}
+}
+
+// WithRequestIDGenerator sets the function generating new request IDs
+// gen: Generator such as [NewUUIDv7] (default) or [NewULID]
+// Returns: Request ID option function
+func WithRequestIDGenerator(gen func() string) RequestIDOption {
+	return func(o *RequestIDOptions) {
+		o.Generator = gen
+	}
+}
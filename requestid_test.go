@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDGenerators(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  func() string
+		re   *regexp.Regexp
+	}{
+		{"uuidv7", NewUUIDv7, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{"ulid", NewULID, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.gen(), tt.gen()
+
+			if !tt.re.MatchString(a) {
+				t.Errorf("generated ID %q does not match %s", a, tt.re)
+			}
+
+			if a == b {
+				t.Errorf("generated IDs are equal: %q", a)
+			}
+		})
+	}
+}
+
+func TestEnsureRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		opts    []RequestIDOption
+		want    string
+	}{
+		{"default header", map[string]string{"X-Request-ID": "abc"}, nil, "abc"},
+		{
+			"configured headers in order",
+			map[string]string{"X-Correlation-ID": "corr"},
+			[]RequestIDOption{WithRequestIDHeaders("X-Request-ID", "X-Correlation-ID")},
+			"corr",
+		},
+		{"generated", nil, []RequestIDOption{WithRequestIDGenerator(func() string { return "gen" })}, "gen"},
+		{
+			"invalid characters replaced",
+			map[string]string{"X-Request-ID": "abc\" injected=1"},
+			[]RequestIDOption{WithRequestIDGenerator(func() string { return "gen" })},
+			"gen",
+		},
+		{
+			"oversized replaced",
+			map[string]string{"X-Request-ID": strings.Repeat("a", 129)},
+			[]RequestIDOption{WithRequestIDGenerator(func() string { return "gen" })},
+			"gen",
+		},
+		{
+			"next header after an invalid one",
+			map[string]string{"X-Request-ID": "a b", "X-Correlation-ID": "corr"},
+			[]RequestIDOption{WithRequestIDHeaders("X-Request-ID", "X-Correlation-ID")},
+			"corr",
+		},
+		{
+			"custom validator",
+			map[string]string{"X-Request-ID": "a b"},
+			[]RequestIDOption{WithRequestIDValidator(nil)},
+			"a b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			ctx, id := EnsureRequestID(r, tt.opts...)
+
+			if id != tt.want || RequestIDFromContext(ctx) != tt.want {
+				t.Errorf("EnsureRequestID() = %q, context %q, want %q", id, RequestIDFromContext(ctx), tt.want)
+			}
+
+			h := http.Header{}
+			SetRequestIDHeader(ctx, h, tt.opts...)
+
+			if got := RequestIDFromHeader(h, tt.opts...); got != tt.want {
+				t.Errorf("SetRequestIDHeader() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{NewUUIDv7(), true},
+		{NewULID(), true},
+		{"svc.req_1-a", true},
+		{strings.Repeat("a", 128), true},
+		{strings.Repeat("a", 129), false},
+		{"", false},
+		{"a b", false},
+		{"a\nb", false},
+		{"a=b", false},
+		{"é", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidRequestID(tt.id); got != tt.want {
+			t.Errorf("ValidRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestRequestIDInRecords(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(NewContextHandler(slog.NewTextHandler(&buf, nil)))
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithLogger(ctx, &Logger{logger})
+
+	LoggerFromContext(ctx).InfoContext(ctx, "msg")
+
+	if !strings.Contains(buf.String(), "request_id=req-1") {
+		t.Errorf("output = %q, want request_id=req-1", buf.String())
+	}
+}
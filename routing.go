@@ -0,0 +1,329 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// RouteMatcher reports whether a record belongs to a route.
+// attrs holds the top-level attributes of the context, the logger (added by With)
+// and the record, in that order; later ones take precedence over earlier ones
+// with the same key.
+type RouteMatcher func(ctx context.Context, r slog.Record, attrs []slog.Attr) bool
+
+// Route sends the records matching a predicate to one or more handlers
+type Route struct {
+	Match    RouteMatcher   // Predicate selecting the records of the route
+	Handlers []slog.Handler // Handlers receiving the matching records
+}
+
+// RoutingOptions contains configuration for the routing handler
+type RoutingOptions struct {
+	Routes     []Route        // Routes in evaluation order
+	Default    []slog.Handler // Handlers receiving the records matching no route
+	AllMatches bool           // Whether records go to every matching route instead of the first one
+}
+
+// RoutingOption defines a function type for configuring RoutingOptions
+type RoutingOption func(*RoutingOptions)
+
+// newRoutingOptions applies the options on top of the default configuration
+func newRoutingOptions(opts ...RoutingOption) *RoutingOptions {
+	config := &RoutingOptions{}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// RoutingHandler is a [slog.Handler] sending records to different handlers by predicate,
+// such as audit records to an audit file or the records of a component to a separate sink.
+//
+// Routes are evaluated in order. By default a record goes to the handlers of the first
+// matching route only; with [RoutingOptions.AllMatches] it goes to the handlers of every
+// matching route. Records matching no route go to the [RoutingOptions.Default] handlers.
+// Each handler receives only the records it enables.
+type RoutingHandler struct {
+	routes     []Route
+	defaults   []slog.Handler
+	allMatches bool
+	attrs      []slog.Attr // top-level attributes added via WithAttrs
+	grouped    bool        // whether WithGroup was called, hiding record attributes from matchers
+}
+
+// NewRoutingHandler creates a handler routing records by predicate
+// opts: Variadic list of configuration options
+// Returns: Routing handler
+func NewRoutingHandler(opts ...RoutingOption) *RoutingHandler {
+	config := newRoutingOptions(opts...)
+
+	return &RoutingHandler{
+		routes:     config.Routes,
+		defaults:   config.Default,
+		allMatches: config.AllMatches,
+	}
+}
+
+// RoutingMiddleware returns a middleware routing records, for use with [WithMiddleware].
+// The wrapped handler is added to the default route, so that records matching no route
+// are logged as without the middleware.
+// opts: Variadic list of configuration options
+// Returns: Routing middleware
+func RoutingMiddleware(opts ...RoutingOption) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewRoutingHandler(append(opts[:len(opts):len(opts)], WithDefaultRoute(next))...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *RoutingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, route := range h.routes {
+		if anyEnabled(ctx, route.Handlers, level) {
+			return true
+		}
+	}
+
+	return anyEnabled(ctx, h.defaults, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *RoutingHandler) Handle(ctx context.Context, r slog.Record) error {
+	ctxAttrs := AttrsFromContext(ctx)
+
+	attrs := make([]slog.Attr, 0, len(ctxAttrs)+len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, ctxAttrs...)
+	attrs = append(attrs, h.attrs...)
+
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			attrs = append(attrs, a)
+
+			return true
+		})
+	}
+
+	var (
+		errs    []error
+		matched bool
+	)
+
+	for _, route := range h.routes {
+		if !route.Match(ctx, r, attrs) {
+			continue
+		}
+
+		matched = true
+		errs = append(errs, handleAll(ctx, route.Handlers, r)...)
+
+		if !h.allMatches {
+			break
+		}
+	}
+
+	if !matched {
+		errs = append(errs, handleAll(ctx, h.defaults, r)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *RoutingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := h.derive(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+
+	if !h.grouped {
+		h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+	}
+
+	return h2
+}
+
+// WithGroup implements [slog.Handler]
+func (h *RoutingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := h.derive(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+	h2.grouped = true
+
+	return h2
+}
+
+// derive returns a copy of the handler with every route handler derived by fn
+func (h *RoutingHandler) derive(fn func(slog.Handler) slog.Handler) *RoutingHandler {
+	h2 := *h
+
+	h2.routes = make([]Route, len(h.routes))
+	for i, route := range h.routes {
+		h2.routes[i] = Route{Match: route.Match, Handlers: deriveAll(route.Handlers, fn)}
+	}
+
+	h2.defaults = deriveAll(h.defaults, fn)
+
+	return &h2
+}
+
+// deriveAll applies fn to every handler
+func deriveAll(handlers []slog.Handler, fn func(slog.Handler) slog.Handler) []slog.Handler {
+	derived := make([]slog.Handler, len(handlers))
+	for i, next := range handlers {
+		derived[i] = fn(next)
+	}
+
+	return derived
+}
+
+// anyEnabled reports whether one of the handlers enables the level
+func anyEnabled(ctx context.Context, handlers []slog.Handler, level slog.Level) bool {
+	for _, next := range handlers {
+		if next.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleAll passes a copy of the record to every handler enabling its level
+func handleAll(ctx context.Context, handlers []slog.Handler, r slog.Record) []error {
+	var errs []error
+
+	for _, next := range handlers {
+		if !next.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		if err := next.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// lookupAttr returns the value of the last attribute with the key
+func lookupAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value.Resolve(), true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// RouteMinLevel matches records logged at the level or above
+// level: Lowest matching level
+// Returns: Route matcher
+func RouteMinLevel(level Level) RouteMatcher {
+	return func(_ context.Context, r slog.Record, _ []slog.Attr) bool {
+		return r.Level >= level
+	}
+}
+
+// RouteName matches records of the logger with the name, set by [Logger.Named],
+// and of its sub-loggers
+// name: Dot-separated logger name such as "payments" or "payments.stripe"
+// Returns: Route matcher
+func RouteName(name string) RouteMatcher {
+	return func(_ context.Context, _ slog.Record, attrs []slog.Attr) bool {
+		v, ok := lookupAttr(attrs, NameKey)
+		if !ok {
+			return false
+		}
+
+		s := v.String()
+
+		return s == name || strings.HasPrefix(s, name+".")
+	}
+}
+
+// RouteMessagePrefix matches records whose message starts with the prefix
+// prefix: Message prefix
+// Returns: Route matcher
+func RouteMessagePrefix(prefix string) RouteMatcher {
+	return func(_ context.Context, r slog.Record, _ []slog.Attr) bool {
+		return strings.HasPrefix(r.Message, prefix)
+	}
+}
+
+// RouteAttr matches records having the attribute with the value, compared by their
+// string forms, so that RouteAttr("audit", true) matches both Bool("audit", true) and
+// String("audit", "true")
+// key: Top-level attribute key
+// value: Expected value
+// Returns: Route matcher
+func RouteAttr(key string, value any) RouteMatcher {
+	want := fmt.Sprint(value)
+
+	return func(_ context.Context, _ slog.Record, attrs []slog.Attr) bool {
+		v, ok := lookupAttr(attrs, key)
+
+		return ok && v.String() == want
+	}
+}
+
+// RouteAll matches records matching every matcher; without matchers it matches every record
+// matchers: Matchers to combine
+// Returns: Route matcher
+func RouteAll(matchers ...RouteMatcher) RouteMatcher {
+	return func(ctx context.Context, r slog.Record, attrs []slog.Attr) bool {
+		for _, m := range matchers {
+			if !m(ctx, r, attrs) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// RouteAny matches records matching at least one matcher
+// matchers: Matchers to combine
+// Returns: Route matcher
+func RouteAny(matchers ...RouteMatcher) RouteMatcher {
+	return func(ctx context.Context, r slog.Record, attrs []slog.Attr) bool {
+		for _, m := range matchers {
+			if m(ctx, r, attrs) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// WithRoute adds a route, evaluated after the routes added before it
+// match: Predicate selecting the records of the route
+// handlers: Handlers receiving the matching records
+// Returns: Routing option function
+func WithRoute(match RouteMatcher, handlers ...slog.Handler) RoutingOption {
+	return func(o *RoutingOptions) {
+		o.Routes = append(o.Routes, Route{Match: match, Handlers: handlers})
+	}
+}
+
+// WithDefaultRoute adds handlers receiving the records matching no route
+// handlers: Default handlers
+// Returns: Routing option function
+func WithDefaultRoute(handlers ...slog.Handler) RoutingOption {
+	return func(o *RoutingOptions) {
+		o.Default = append(o.Default, handlers...)
+	}
+}
+
+// WithRouteAllMatches sets whether records go to every matching route
+// instead of the first one only
+// allMatches: Whether all matching routes receive the records
+// Returns: Routing option function
+func WithRouteAllMatches(allMatches bool) RoutingOption {
+	return func(o *RoutingOptions) {
+		o.AllMatches = allMatches
+	}
+}
@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRoutingHandler(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), String("component", "payments"))
+
+	tests := []struct {
+		name       string
+		allMatches bool
+		log        func(l *Logger)
+		want       [3]string // audit, payments and default outputs
+	}{
+		{
+			"attribute",
+			false,
+			func(l *Logger) {
+				l.Info("login", "audit", true)
+				l.Info("request")
+			},
+			[3]string{"msg=login audit=true", "", "msg=request"},
+		},
+		{
+			"first match",
+			false,
+			func(l *Logger) {
+				l.Info("refund", "audit", true, "component", "payments")
+			},
+			[3]string{"msg=refund audit=true component=payments", "", ""},
+		},
+		{
+			"all matches",
+			true,
+			func(l *Logger) {
+				l.Info("refund", "audit", true, "component", "payments")
+				l.Info("request")
+			},
+			[3]string{"msg=refund audit=true component=payments", "msg=refund audit=true component=payments", "msg=request"},
+		},
+		{
+			"logger attribute",
+			false,
+			func(l *Logger) {
+				l.With("component", "payments").Info("charged")
+			},
+			[3]string{"", "msg=charged component=payments", ""},
+		},
+		{
+			"context attribute",
+			false,
+			func(l *Logger) {
+				l.InfoContext(ctx, "charged")
+			},
+			[3]string{"", "msg=charged", ""},
+		},
+		{
+			"record attribute takes precedence",
+			false,
+			func(l *Logger) {
+				l.InfoContext(ctx, "charged", "component", "orders")
+			},
+			[3]string{"", "", "msg=charged component=orders"},
+		},
+		{
+			"grouped attribute",
+			false,
+			func(l *Logger) {
+				l.WithGroup("g").Info("login", "audit", true)
+			},
+			[3]string{"", "", "msg=login g.audit=true"},
+		},
+		{
+			"logger name",
+			false,
+			func(l *Logger) {
+				l.Named("payments").Named("stripe").Info("charged")
+				l.Named("paymentsx").Info("charged")
+			},
+			[3]string{"", "msg=charged logger=payments.stripe", "msg=charged logger=paymentsx"},
+		},
+		{
+			"message prefix",
+			false,
+			func(l *Logger) {
+				l.Info("payment: charged")
+			},
+			[3]string{"", "msg=\"payment: charged\"", ""},
+		},
+		{
+			"level",
+			false,
+			func(l *Logger) {
+				l.Error("failed")
+				l.Debug("skipped")
+			},
+			[3]string{"msg=failed", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var audit, payments, def bytes.Buffer
+
+			newHandler := func(buf *bytes.Buffer) slog.Handler {
+				return slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel})
+			}
+
+			logger := &Logger{Logger: slog.New(NewRoutingHandler(
+				WithRoute(RouteAny(RouteAttr("audit", true), RouteMinLevel(LevelError)), newHandler(&audit)),
+				WithRoute(RouteAny(RouteAttr("component", "payments"), RouteName("payments"), RouteMessagePrefix("payment:")),
+					newHandler(&payments)),
+				WithDefaultRoute(newHandler(&def)),
+				WithRouteAllMatches(tt.allMatches),
+			))}
+
+			tt.log(logger)
+
+			for i, buf := range []*bytes.Buffer{&audit, &payments, &def} {
+				if got := strings.TrimSpace(buf.String()); got != tt.want[i] {
+					t.Errorf("output %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRoutingHandlerEnabled(t *testing.T) {
+	info := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	errorsOnly := slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: LevelError})
+
+	h := NewRoutingHandler(WithRoute(RouteAll(), errorsOnly), WithDefaultRoute(info))
+
+	if !h.Enabled(context.Background(), LevelInfo) || h.Enabled(context.Background(), LevelDebug) {
+		t.Error("Enabled does not follow the most verbose handler")
+	}
+
+	var buf bytes.Buffer
+
+	h = NewRoutingHandler(WithRoute(RouteAll(), slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelError})))
+	slog.New(h).Info("dropped")
+
+	if buf.Len() != 0 {
+		t.Errorf("handler received a record below its level: %q", buf.String())
+	}
+}
+
+func TestRoutingMiddleware(t *testing.T) {
+	var out, audit bytes.Buffer
+
+	logger := NewLogger(WithWriter(&out), WithMiddleware(RoutingMiddleware(
+		WithRoute(RouteAttr("audit", true), slog.NewTextHandler(&audit, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel})),
+	)))
+
+	logger.Info("login", "audit", true)
+	logger.Info("request")
+
+	if got, want := audit.String(), "msg=login audit=true\n"; got != want {
+		t.Errorf("audit output = %q, want %q", got, want)
+	}
+
+	if got := out.String(); strings.Contains(got, "login") || !strings.Contains(got, "msg=request") {
+		t.Errorf("output = %q, want only the unmatched record", got)
+	}
+}
+
+func TestRoutingHandlerErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	h := NewRoutingHandler(
+		WithRoute(RouteAll(), failingHandler{errA}, failingHandler{errB}),
+	)
+
+	err := h.Handle(context.Background(), slog.NewRecord(goldenTime, LevelInfo, "msg", 0))
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Handle() = %v, want both handler errors", err)
+	}
+}
+
+// failingHandler is a handler whose Handle always fails
+type failingHandler struct {
+	err error
+}
+
+func (h failingHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+func (h failingHandler) WithAttrs([]slog.Attr) slog.Handler        { return h }
+func (h failingHandler) WithGroup(string) slog.Handler             { return h }
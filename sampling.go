@@ -0,0 +1,296 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// Default sampling configuration constants
+	defaultSampleInterval   = time.Second
+	defaultSampleFirst      = 100
+	defaultSampleThereafter = 100
+	defaultSampleRate       = 1.0
+	defaultSampleMaxLevel   = LevelWarn
+
+	// sampleSummaryMessage is the message of records reporting suppressed records
+	sampleSummaryMessage = "log records sampled"
+)
+
+// SamplingOptions contains configuration for the sampling handler
+type SamplingOptions struct {
+	Interval   time.Duration // Length of the counting window
+	First      int           // Records logged per window for each level and message
+	Thereafter int           // After First, every Thereafter-th record is logged, 0 drops them all
+	Rate       float64       // Probability of logging a record kept by the counters, 1 keeps all
+	MaxLevel   Level         // Highest sampled level, records above it are always logged
+}
+
+// SamplingOption defines a function type for configuring SamplingOptions
+type SamplingOption func(*SamplingOptions)
+
+// newSamplingOptions applies the options on top of the default configuration
+func newSamplingOptions(opts ...SamplingOption) *SamplingOptions {
+	config := &SamplingOptions{
+		Interval:   defaultSampleInterval,
+		First:      defaultSampleFirst,
+		Thereafter: defaultSampleThereafter,
+		Rate:       defaultSampleRate,
+		MaxLevel:   defaultSampleMaxLevel,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// sampleKey identifies the records counted together
+type sampleKey struct {
+	level   Level
+	message string
+}
+
+// sampler holds the counters shared by a SamplingHandler and the handlers derived from it
+type sampler struct {
+	opts    *SamplingOptions
+	summary slog.Handler // handler receiving summary records, without attributes or groups
+
+	mu          sync.Mutex
+	windowStart time.Time // time of the first record of the window, zero between windows
+	windowWall  time.Time // wall clock time at which the window started
+	counts      map[sampleKey]int
+	suppressed  int
+	gen         uint64 // window counter invalidating stale timers
+	timer       *time.Timer
+}
+
+// SamplingHandler is a [slog.Handler] middleware that limits repeated records.
+// In every window of [SamplingOptions.Interval], the first [SamplingOptions.First]
+// records with the same level and message are logged, then every
+// [SamplingOptions.Thereafter]-th one; records kept by the counters are then logged
+// with the probability [SamplingOptions.Rate]. Records above [SamplingOptions.MaxLevel]
+// (Error and above by default) are never sampled.
+//
+// When a window with suppressed records ends, a Warn summary record with the
+// "suppressed" count of the window is logged: by a timer when the window closes,
+// or before the first record of the next window if that comes first. Summaries are
+// handled with context.Background(), as they do not belong to any request.
+// [SamplingHandler.Flush] logs the summary of the current window, for example before exiting.
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+// NewSamplingHandler wraps a handler with sampling
+// next: Handler receiving the sampled records
+// opts: Variadic list of configuration options
+// Returns: Sampling handler
+func NewSamplingHandler(next slog.Handler, opts ...SamplingOption) *SamplingHandler {
+	return &SamplingHandler{
+		next: next,
+		sampler: &sampler{
+			opts:    newSamplingOptions(opts...),
+			summary: next,
+			counts:  make(map[sampleKey]int),
+		},
+	}
+}
+
+// SamplingMiddleware returns a middleware sampling records, for use with [WithMiddleware]
+// opts: Variadic list of configuration options
+// Returns: Sampling middleware
+func SamplingMiddleware(opts ...SamplingOption) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return NewSamplingHandler(next, opts...)
+	}
+}
+
+// Enabled implements [slog.Handler]
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler]
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level > h.sampler.opts.MaxLevel {
+		return h.next.Handle(ctx, r)
+	}
+
+	keep, summary := h.sampler.sample(r)
+
+	if summary != nil {
+		// the summary covers other records, so it does not carry the context of this one
+		_ = h.sampler.summary.Handle(context.Background(), *summary)
+	}
+
+	if !keep {
+		return nil
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements [slog.Handler]
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup implements [slog.Handler]
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &SamplingHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// Flush logs the summary of the records suppressed in the current window and starts a new one
+func (h *SamplingHandler) Flush() {
+	s := h.sampler
+
+	s.mu.Lock()
+	summary := s.endWindow(time.Now())
+	s.mu.Unlock()
+
+	if summary != nil {
+		_ = s.summary.Handle(context.Background(), *summary)
+	}
+}
+
+// sample counts the record and reports whether it is logged, along with the
+// summary record of the previous window when the record starts a new one
+func (s *sampler) sample(r slog.Record) (bool, *slog.Record) {
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary *slog.Record
+
+	if !s.windowStart.IsZero() && now.Sub(s.windowStart) >= s.opts.Interval {
+		summary = s.endWindow(now)
+	}
+
+	if s.windowStart.IsZero() {
+		s.windowStart = now
+		s.windowWall = time.Now()
+	}
+
+	key := sampleKey{level: r.Level, message: r.Message}
+	s.counts[key]++
+	n := s.counts[key]
+
+	keep := n <= s.opts.First || (s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0)
+	if keep && s.opts.Rate < 1 {
+		keep = rand.Float64() < s.opts.Rate
+	}
+
+	if !keep {
+		s.suppressed++
+
+		if s.suppressed == 1 {
+			gen := s.gen
+			s.timer = time.AfterFunc(s.opts.Interval-time.Since(s.windowWall), func() { s.expire(gen) })
+		}
+	}
+
+	return keep, summary
+}
+
+// endWindow closes the current window and returns its summary record, or nil when
+// no record was suppressed. It must be called with the mutex held.
+func (s *sampler) endWindow(now time.Time) *slog.Record {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	var summary *slog.Record
+
+	if s.suppressed > 0 {
+		rec := slog.NewRecord(now, LevelWarn, sampleSummaryMessage, 0)
+		rec.AddAttrs(Int("suppressed", s.suppressed), Duration("interval", s.opts.Interval))
+		summary = &rec
+	}
+
+	clear(s.counts)
+	s.suppressed = 0
+	s.windowStart = time.Time{}
+	s.gen++
+
+	return summary
+}
+
+// expire logs the summary of the window of the given generation when it closes
+func (s *sampler) expire(gen uint64) {
+	s.mu.Lock()
+
+	if s.gen != gen {
+		s.mu.Unlock()
+
+		return
+	}
+
+	summary := s.endWindow(time.Now())
+
+	s.mu.Unlock()
+
+	if summary != nil {
+		_ = s.summary.Handle(context.Background(), *summary)
+	}
+}
+
+// WithSampleInterval sets the length of the counting window
+// interval: Window length
+// Returns: Sampling option function
+func WithSampleInterval(interval time.Duration) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Interval = interval
+	}
+}
+
+// WithSampleFirst sets how many records with the same level and message are logged per window
+// first: Number of records logged before sampling starts
+// Returns: Sampling option function
+func WithSampleFirst(first int) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.First = first
+	}
+}
+
+// WithSampleThereafter sets the sampling period after the first records of a window
+// thereafter: Every thereafter-th record is logged, 0 drops them all
+// Returns: Sampling option function
+func WithSampleThereafter(thereafter int) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Thereafter = thereafter
+	}
+}
+
+// WithSampleRate sets the probability of logging a record kept by the counters.
+// Combine it with WithSampleFirst(0) and WithSampleThereafter(1) for purely
+// probabilistic sampling.
+// rate: Probability between 0 and 1, clamped to that range
+// Returns: Sampling option function
+func WithSampleRate(rate float64) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.Rate = min(max(rate, 0), 1)
+	}
+}
+
+// WithSampleMaxLevel sets the highest sampled level; records above it are always logged
+// level: Highest sampled level, Warn by default
+// Returns: Sampling option function
+func WithSampleMaxLevel(level Level) SamplingOption {
+	return func(o *SamplingOptions) {
+		o.MaxLevel = level
+	}
+}
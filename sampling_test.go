@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    []SamplingOption
+		level   Level
+		count   int
+		spacing time.Duration
+		want    int
+	}{
+		{"first then every third", []SamplingOption{WithSampleFirst(2), WithSampleThereafter(3)}, LevelInfo, 10, 0, 4},
+		{"drop after first", []SamplingOption{WithSampleFirst(2), WithSampleThereafter(0)}, LevelInfo, 10, 0, 2},
+		{"new window", []SamplingOption{WithSampleFirst(1), WithSampleThereafter(0)}, LevelInfo, 4, time.Second, 4},
+		{"error never sampled", []SamplingOption{WithSampleFirst(1), WithSampleThereafter(0)}, LevelError, 5, 0, 5},
+		{
+			"max level",
+			[]SamplingOption{WithSampleFirst(1), WithSampleThereafter(0), WithSampleMaxLevel(LevelError)},
+			LevelError,
+			5,
+			0,
+			1,
+		},
+		{"rate zero", []SamplingOption{WithSampleFirst(0), WithSampleThereafter(1), WithSampleRate(0)}, LevelInfo, 5, 0, 0},
+		{"rate one", []SamplingOption{WithSampleFirst(0), WithSampleThereafter(1), WithSampleRate(2)}, LevelInfo, 5, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelDebug}), tt.opts...)
+
+			for i := range tt.count {
+				r := slog.NewRecord(start.Add(time.Duration(i)*tt.spacing), tt.level, "repeated", 0)
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := strings.Count(buf.String(), "msg=repeated"); got != tt.want {
+				t.Errorf("logged %d records, want %d:\n%s", got, tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestSamplingHandlerKeys(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(NewSamplingHandler(slog.NewTextHandler(&buf, nil), WithSampleFirst(1), WithSampleThereafter(0)))
+
+	logger.Info("a")
+	logger.Info("a")
+	logger.Info("b")
+	logger.Warn("a")
+	logger.With("k", "v").Info("a") // derived handlers share the counters
+
+	for _, want := range []string{"level=INFO msg=a", "level=INFO msg=b", "level=WARN msg=a"} {
+		if got := strings.Count(buf.String(), want); got != 1 {
+			t.Errorf("output has %d %q records, want 1:\n%s", got, want, buf.String())
+		}
+	}
+}
+
+func TestSamplingSummary(t *testing.T) {
+	var buf bytes.Buffer
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithSampleFirst(1), WithSampleThereafter(0), WithSampleInterval(time.Minute)).WithAttrs([]slog.Attr{String("k", "v")})
+
+	for _, offset := range []time.Duration{0, time.Second, 2 * time.Second, time.Minute, 2 * time.Minute} {
+		_ = h.Handle(context.Background(), slog.NewRecord(start.Add(offset), LevelInfo, "repeated", 0))
+	}
+
+	want := "msg=repeated k=v\n" +
+		`msg="log records sampled" suppressed=2 interval=1m0s` + "\n" +
+		"msg=repeated k=v\n" +
+		"msg=repeated k=v\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSamplingSummaryContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewSamplingHandler(NewContextHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel})),
+		WithSampleFirst(1), WithSampleThereafter(0), WithSampleInterval(time.Minute))
+
+	ctx1 := ContextWithAttrs(context.Background(), String("request_id", "r1"))
+	ctx2 := ContextWithAttrs(context.Background(), String("request_id", "r2"))
+
+	_ = h.Handle(ctx1, slog.NewRecord(start, LevelInfo, "repeated", 0))
+	_ = h.Handle(ctx1, slog.NewRecord(start.Add(time.Second), LevelInfo, "repeated", 0))
+	_ = h.Handle(ctx2, slog.NewRecord(start.Add(time.Minute), LevelInfo, "repeated", 0))
+
+	// the summary is not attributed to the request opening the next window
+	want := "msg=repeated request_id=r1\n" +
+		`msg="log records sampled" suppressed=1 interval=1m0s` + "\n" +
+		"msg=repeated request_id=r2\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSamplingSummaryTimer(t *testing.T) {
+	var buf syncBuffer
+
+	logger := slog.New(NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithSampleFirst(1), WithSampleThereafter(0), WithSampleInterval(20*time.Millisecond)))
+
+	for range 3 {
+		logger.Info("repeated")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "suppressed") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	want := "msg=repeated\n" + `msg="log records sampled" suppressed=2 interval=20ms` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSamplingHandlerFlush(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTimeAndLevel}),
+		WithSampleFirst(1), WithSampleThereafter(0))
+	logger := slog.New(h)
+
+	logger.Info("repeated")
+	logger.Info("repeated")
+	h.Flush()
+	h.Flush()
+	logger.Info("repeated") // a new window starts after Flush
+
+	want := "msg=repeated\n" + `msg="log records sampled" suppressed=1 interval=1s` + "\nmsg=repeated\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkSamplingHandler(b *testing.B) {
+	logger := slog.New(NewSamplingHandler(slog.NewTextHandler(io.Discard, nil)))
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("request handled", "status", 200)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package logging
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ScrubDetector is a set of flags selecting the PII detectors applied to string values
+type ScrubDetector uint
+
+const (
+	// ScrubEmail masks e-mail addresses, keeping the first character and the domain
+	ScrubEmail ScrubDetector = 1 << iota
+
+	// ScrubPhone masks phone numbers of 10 to 15 digits, keeping the last two digits
+	ScrubPhone
+
+	// ScrubCreditCard masks card numbers passing the Luhn check, keeping the last four digits
+	ScrubCreditCard
+
+	// ScrubIP masks IPv4 and IPv6 addresses, keeping the network prefix
+	ScrubIP
+
+	// ScrubJWT masks the payload and signature of JSON Web Tokens
+	ScrubJWT
+
+	// ScrubAWSKey masks AWS access key IDs, keeping the key type prefix
+	ScrubAWSKey
+
+	// ScrubNone disables scrubbing
+	ScrubNone ScrubDetector = 0
+
+	// ScrubAll enables every detector
+	ScrubAll = ScrubEmail | ScrubPhone | ScrubCreditCard | ScrubIP | ScrubJWT | ScrubAWSKey
+)
+
+const (
+	// defaultScrubDetectors disables scrubbing by default
+	defaultScrubDetectors = ScrubNone
+
+	// scrubMaskChar replaces masked characters
+	scrubMaskChar = '*'
+)
+
+var (
+	emailRegexp      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRegexp      = regexp.MustCompile(`\+?\(?\d[\d ().\-]{8,}\d`)
+	creditCardRegexp = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	ipv4Regexp       = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`)
+	ipv6Regexp       = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+	jwtRegexp        = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	awsKeyRegexp     = regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b`)
+)
+
+// detector finds and masks a single kind of PII
+type detector struct {
+	flag     ScrubDetector
+	re       *regexp.Regexp
+	precheck func(s string) bool // cheap test that rules out strings without candidates
+	mask     func(match string) string
+}
+
+// detectors are applied in order; more specific patterns run first
+// so that their masked output is not matched again by broader ones
+var detectors = []detector{
+	{ScrubJWT, jwtRegexp, func(s string) bool { return strings.Contains(s, "eyJ") }, maskJWT},
+	{ScrubAWSKey, awsKeyRegexp, containsUpperA, maskAWSKey},
+	{ScrubEmail, emailRegexp, func(s string) bool { return strings.Contains(s, "@") }, maskEmail},
+	{ScrubCreditCard, creditCardRegexp, containsDigit, maskCreditCard},
+	{ScrubIP, ipv6Regexp, func(s string) bool { return strings.Contains(s, ":") }, maskIPv6},
+	{ScrubIP, ipv4Regexp, func(s string) bool { return strings.Contains(s, ".") }, maskIPv4},
+	{ScrubPhone, phoneRegexp, containsDigit, maskPhone},
+}
+
+// scrubber applies the enabled detectors to string values
+type scrubber struct {
+	detectors []detector
+}
+
+// newScrubber returns a scrubber for the enabled detectors or nil if none are enabled
+func newScrubber(flags ScrubDetector) *scrubber {
+	if flags == ScrubNone {
+		return nil
+	}
+
+	s := &scrubber{}
+
+	for _, d := range detectors {
+		if flags&d.flag != 0 {
+			s.detectors = append(s.detectors, d)
+		}
+	}
+
+	return s
+}
+
+// scrub masks every detected PII occurrence in the string
+func (s *scrubber) scrub(str string) string {
+	if s == nil {
+		return str
+	}
+
+	for _, d := range s.detectors {
+		if d.precheck(str) {
+			str = d.re.ReplaceAllStringFunc(str, d.mask)
+		}
+	}
+
+	return str
+}
+
+// scrubAttr masks PII in string-valued attributes, including the message, and in the
+// rendered form of errors and other values of kind Any, which are then logged as strings
+func (o *Options) scrubAttr(a slog.Attr) slog.Attr {
+	if o.scrubber == nil {
+		return a
+	}
+
+	var str string
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		str = a.Value.String()
+	case slog.KindAny:
+		var ok bool
+		if str, ok = renderAny(a.Value.Any()); !ok {
+			return a
+		}
+	default:
+		return a
+	}
+
+	if scrubbed := o.scrubber.scrub(str); scrubbed != str {
+		a.Value = slog.StringValue(scrubbed)
+	}
+
+	return a
+}
+
+// renderAny returns the string form of a value of kind Any as handlers log it.
+// It reports false without formatting values that cannot hold PII: the source and
+// level of records, and numbers and booleans, alone or in slices and arrays.
+func renderAny(v any) (string, bool) {
+	switch v := v.(type) {
+	case *slog.Source, slog.Level:
+		return "", false
+	case error:
+		return v.Error(), true
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return string(text), true
+		}
+	case []byte:
+		return string(v), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+
+	if t := reflect.TypeOf(v); t != nil && (isScalarKind(t.Kind()) ||
+		(t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isScalarKind(t.Elem().Kind())) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%+v", v), true
+}
+
+// isScalarKind reports whether values of a kind are formatted without any text
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+
+	return false
+}
+
+// maskEmail keeps the first character of the local part and the domain
+func maskEmail(m string) string {
+	local, domain, _ := strings.Cut(m, "@")
+
+	return local[:1] + strings.Repeat(string(scrubMaskChar), len(local)-1) + "@" + domain
+}
+
+// maskPhone keeps the last two digits of numbers with 10 to 15 digits
+func maskPhone(m string) string {
+	if n := countDigits(m); n < 10 || n > 15 {
+		return m
+	}
+
+	return maskDigits(m, 2)
+}
+
+// maskCreditCard keeps the last four digits of numbers passing the Luhn check
+func maskCreditCard(m string) string {
+	if !luhnValid(m) {
+		return m
+	}
+
+	return maskDigits(m, 4)
+}
+
+// maskIPv4 keeps the first two octets
+func maskIPv4(m string) string {
+	parts := strings.SplitN(m, ".", 4)
+
+	return parts[0] + "." + parts[1] + ".*.*"
+}
+
+// maskIPv6 keeps the first two groups of valid IPv6 addresses
+func maskIPv6(m string) string {
+	addr, err := netip.ParseAddr(m)
+	if err != nil || !addr.Is6() {
+		return m
+	}
+
+	parts := strings.SplitN(addr.StringExpanded(), ":", 3)
+
+	return parts[0] + ":" + parts[1] + ":*"
+}
+
+// maskJWT keeps the header and masks the payload and signature
+func maskJWT(m string) string {
+	header, _, _ := strings.Cut(m, ".")
+
+	return header + ".***.***"
+}
+
+// maskAWSKey keeps the four-letter key type prefix
+func maskAWSKey(m string) string {
+	return m[:4] + strings.Repeat(string(scrubMaskChar), len(m)-4)
+}
+
+// maskDigits replaces all but the last keep digits, preserving separators
+func maskDigits(s string, keep int) string {
+	b := []byte(s)
+	left := countDigits(s) - keep
+
+	for i := 0; i < len(b) && left > 0; i++ {
+		if b[i] >= '0' && b[i] <= '9' {
+			b[i] = scrubMaskChar
+			left--
+		}
+	}
+
+	return string(b)
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// countDigits returns the number of ASCII digits in s
+func countDigits(s string) int {
+	n := 0
+
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+
+	return n
+}
+
+// containsDigit reports whether s contains an ASCII digit
+func containsDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// containsUpperA reports whether s may contain an AWS key prefix
+func containsUpperA(s string) bool {
+	return strings.Contains(s, "A")
+}
+
+// WithScrub enables value-pattern PII scrubbing of string values and messages
+// detectors: Combination of Scrub* flags, for example ScrubEmail|ScrubCreditCard or [ScrubAll]
+// Returns: Configuration option function
+func WithScrub(detectors ScrubDetector) Option {
+	return func(o *Options) {
+		o.ScrubDetectors = detectors
+	}
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// Secret wraps a sensitive value so that it can never be printed in clear.
+// LogValue, String, GoString, MarshalText, MarshalJSON and Format all return "[REDACTED]",
+// so a secret accidentally passed to [Any], fmt or encoding/json stays masked.
+// Use [Secret.Reveal] to access the wrapped value explicitly.
+//
+// The value is held by a closure rather than a field, so that reflection-based
+// printers, which bypass these methods for secrets stored in unexported fields,
+// only ever see a function pointer.
+type Secret[T any] struct {
+	value func() T
+}
+
+// SecretString is a [Secret] holding a string such as a password or an API token
+type SecretString = Secret[string]
+
+// NewSecret wraps a value into a [Secret]
+// val: The sensitive value
+// Returns: Secret holding the value
+func NewSecret[T any](val T) Secret[T] {
+	return Secret[T]{value: func() T { return val }}
+}
+
+// Sensitive creates an attribute whose value is always logged as "[REDACTED]"
+// key: The attribute key
+// val: The sensitive value
+// Returns: A structured log attribute
+func Sensitive[T any](key string, val T) slog.Attr {
+	return slog.Any(key, NewSecret(val))
+}
+
+// Reveal returns the wrapped value, the zero value for a zero Secret
+func (s Secret[T]) Reveal() T {
+	if s.value == nil {
+		var zero T
+
+		return zero
+	}
+
+	return s.value()
+}
+
+// LogValue implements [slog.LogValuer]
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(redactedValue)
+}
+
+// String implements [fmt.Stringer]
+func (s Secret[T]) String() string {
+	return redactedValue
+}
+
+// GoString implements [fmt.GoStringer]
+func (s Secret[T]) GoString() string {
+	return redactedValue
+}
+
+// Format implements [fmt.Formatter] for every verb
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(redactedValue))
+}
+
+// MarshalText implements [encoding.TextMarshaler]
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(redactedValue), nil
+}
+
+// MarshalJSON implements [json.Marshaler]
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedValue)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], so secrets can be loaded from configuration
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	var val T
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+
+	*s = NewSecret(val)
+
+	return nil
+}
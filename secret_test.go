@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecretFormatting(t *testing.T) {
+	s := NewSecret("p@ssw0rd")
+
+	jsonOut, err := json.Marshal(struct{ S SecretString }{s})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+	}{
+		{"String", s.String()},
+		{"GoString", s.GoString()},
+		{"Sprint", fmt.Sprint(s)},
+		{"Sprintf %v", fmt.Sprintf("%v", s)},
+		{"Sprintf %+v", fmt.Sprintf("%+v", s)},
+		{"Sprintf %#v", fmt.Sprintf("%#v", s)},
+		{"Sprintf %s", fmt.Sprintf("%s", s)},
+		{"Sprintf %q", fmt.Sprintf("%q", s)},
+		{"Sprintf %x", fmt.Sprintf("%x", s)},
+		{"Sprintf %v pointer", fmt.Sprintf("%v", &s)},
+		{"json", string(jsonOut)},
+		{"LogValue", s.LogValue().String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if strings.Contains(tt.got, "p@ssw0rd") || !strings.Contains(tt.got, "[REDACTED]") {
+				t.Errorf("%s = %q, want it masked", tt.name, tt.got)
+			}
+		})
+	}
+}
+
+func TestSecretHandlers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w *bytes.Buffer) slog.Handler
+	}{
+		{"text", func(w *bytes.Buffer) slog.Handler { return slog.NewTextHandler(w, nil) }},
+		{"json", func(w *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(w, nil) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			slog.New(tt.handler(&buf)).LogAttrs(context.Background(), LevelInfo, "msg",
+				Sensitive("password", "p@ssw0rd"),
+				Any("token", NewSecret(987654321)),
+			)
+
+			if strings.Contains(buf.String(), "p@ssw0rd") || strings.Contains(buf.String(), "987654321") {
+				t.Errorf("output = %q, want secrets masked", buf.String())
+			}
+		})
+	}
+}
+
+// secretConfig holds secrets in unexported fields, which fmt prints without
+// calling their methods
+type secretConfig struct {
+	user string
+	pw   SecretString
+	pin  Secret[int]
+}
+
+func TestSecretInUnexportedField(t *testing.T) {
+	cfg := secretConfig{user: "bob", pw: NewSecret("hunter2"), pin: NewSecret(987654321)}
+
+	tests := []struct {
+		name string
+		got  func() string
+	}{
+		{"Sprintf %v", func() string { return fmt.Sprintf("%v", cfg) }},
+		{"Sprintf %+v", func() string { return fmt.Sprintf("%+v", cfg) }},
+		{"Sprintf %#v", func() string { return fmt.Sprintf("%#v", cfg) }},
+		{"Any", func() string { return renderText(t, Any("cfg", cfg)) }},
+		{"Any pointer", func() string { return renderText(t, Any("cfg", &cfg)) }},
+		{"Object", func() string { return renderText(t, Object("cfg", cfg)) }},
+		{"json handler", func() string {
+			var buf bytes.Buffer
+
+			slog.New(slog.NewJSONHandler(&buf, nil)).Info("msg", Any("cfg", cfg), Object("obj", cfg))
+
+			return buf.String()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); strings.Contains(got, "hunter2") || strings.Contains(got, "987654321") {
+				t.Errorf("%s = %q, want secrets hidden", tt.name, got)
+			}
+		})
+	}
+}
+
+func TestSecretReveal(t *testing.T) {
+	var zero SecretString
+	if zero.Reveal() != "" {
+		t.Errorf("zero Reveal() = %q, want empty", zero.Reveal())
+	}
+
+	var s SecretString
+
+	if err := json.Unmarshal([]byte(`"p@ssw0rd"`), &s); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if s.Reveal() != "p@ssw0rd" {
+		t.Errorf("Reveal() = %q, want p@ssw0rd", s.Reveal())
+	}
+}
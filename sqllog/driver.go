@@ -0,0 +1,596 @@
+// Package sqllog wraps database/sql drivers to log queries, executions and
+// transactions through the logger stored in the context (see logging.LoggerFromContext).
+//
+//	db := sql.OpenDB(sqllog.WrapConnector(connector, sqllog.WithSlowThreshold(time.Second)))
+//
+// Records contain the statement text, the argument count, rows affected, the duration
+// and errors. Argument values are not logged unless [WithArgValues] is used.
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sergei-galichev/logging"
+)
+
+const (
+	// Default configuration constants
+	defaultLevel         = logging.LevelDebug
+	defaultSlowThreshold = 0
+	defaultArgValues     = false
+)
+
+// Options contains configuration for the driver wrapper
+type Options struct {
+	Level         logging.Level // Level of successful statements
+	SlowThreshold time.Duration // Statements slower than this are logged at Warn, 0 disables it
+	ArgValues     bool          // Whether to log argument values in addition to their count
+}
+
+// Option defines a function type for configuring Options
+type Option func(*Options)
+
+// newOptions applies the options on top of the default configuration
+func newOptions(opts ...Option) *Options {
+	config := &Options{
+		Level:         defaultLevel,
+		SlowThreshold: defaultSlowThreshold,
+		ArgValues:     defaultArgValues,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// Wrap returns a driver that logs every statement of the wrapped driver.
+// Register it with sql.Register or prefer [WrapConnector] with sql.OpenDB.
+// d: Driver to wrap
+// opts: Variadic list of configuration options
+// Returns: Logging driver
+func Wrap(d driver.Driver, opts ...Option) driver.Driver {
+	return &loggingDriver{Driver: d, opts: newOptions(opts...)}
+}
+
+// WrapConnector returns a connector that logs every statement of the wrapped connector
+// c: Connector to wrap
+// opts: Variadic list of configuration options
+// Returns: Logging connector for sql.OpenDB
+func WrapConnector(c driver.Connector, opts ...Option) driver.Connector {
+	config := newOptions(opts...)
+
+	return &connector{
+		Connector: c,
+		driver:    &loggingDriver{Driver: c.Driver(), opts: config},
+		opts:      config,
+	}
+}
+
+// loggingDriver implements driver.Driver and driver.DriverContext
+type loggingDriver struct {
+	driver.Driver
+	opts *Options
+}
+
+// Open implements [driver.Driver]
+func (d *loggingDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{Conn: c, opts: d.opts}, nil
+}
+
+// OpenConnector implements [driver.DriverContext]
+func (d *loggingDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &connector{Connector: c, driver: d, opts: d.opts}, nil
+	}
+
+	return &connector{Connector: dsnConnector{name: name, driver: d.Driver}, driver: d, opts: d.opts}, nil
+}
+
+// dsnConnector adapts a driver without DriverContext to driver.Connector
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+// Connect implements [driver.Connector]
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+// Driver implements [driver.Connector]
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// connector implements driver.Connector
+type connector struct {
+	driver.Connector
+	driver driver.Driver
+	opts   *Options
+}
+
+// Connect implements [driver.Connector]
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn2, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{Conn: conn2, opts: c.opts}, nil
+}
+
+// Driver implements [driver.Connector]
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// conn wraps a driver connection
+type conn struct {
+	driver.Conn
+	opts *Options
+}
+
+// Prepare implements [driver.Conn]
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext implements [driver.ConnPrepareContext]
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+
+	var (
+		s   driver.Stmt
+		err error
+	)
+
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = cp.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+
+	c.opts.log(ctx, "sql prepare", query, nil, start, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{Stmt: s, conn: c.Conn, query: query, opts: c.opts}, nil
+}
+
+// Begin implements [driver.Conn]
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements [driver.ConnBeginTx]
+func (c *conn) BeginTx(ctx context.Context, txOpts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+
+	var (
+		t   driver.Tx
+		err error
+	)
+
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		t, err = cb.BeginTx(ctx, txOpts)
+	} else {
+		t, err = beginLegacy(ctx, c.Conn, txOpts)
+	}
+
+	c.opts.log(ctx, "sql begin", "", nil, start, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tx{Tx: t, ctx: ctx, opts: c.opts}, nil
+}
+
+// beginLegacy starts a transaction on a connection without ConnBeginTx,
+// rejecting the options it cannot honor as database/sql does
+func beginLegacy(ctx context.Context, c driver.Conn, txOpts driver.TxOptions) (driver.Tx, error) {
+	if txOpts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+
+	if txOpts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+
+	t, err := c.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		_ = t.Rollback()
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ExecContext implements [driver.ExecerContext], falling back to [driver.Execer]
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var (
+		res driver.Result
+		err error
+	)
+
+	switch execer := c.Conn.(type) {
+	case driver.ExecerContext:
+		res, err = execer.ExecContext(ctx, query, args)
+	case driver.Execer:
+		var values []driver.Value
+		if values, err = driverValues(ctx, args); err == nil {
+			res, err = execer.Exec(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	c.opts.log(ctx, "sql exec", query, args, start, err, rowsAffected(res, err)...)
+
+	return res, err
+}
+
+// QueryContext implements [driver.QueryerContext], falling back to [driver.Queryer]
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var (
+		rows driver.Rows
+		err  error
+	)
+
+	switch queryer := c.Conn.(type) {
+	case driver.QueryerContext:
+		rows, err = queryer.QueryContext(ctx, query, args)
+	case driver.Queryer:
+		var values []driver.Value
+		if values, err = driverValues(ctx, args); err == nil {
+			rows, err = queryer.Query(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	c.opts.log(ctx, "sql query", query, args, start, err)
+
+	return rows, err
+}
+
+// Ping implements [driver.Pinger]
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+// ResetSession implements [driver.SessionResetter]
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+// IsValid implements [driver.Validator]
+func (c *conn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+// CheckNamedValue implements [driver.NamedValueChecker]
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+// stmt wraps a prepared statement
+type stmt struct {
+	driver.Stmt
+	conn  driver.Conn // wrapped connection, whose argument checks the statement follows
+	query string
+	opts  *Options
+}
+
+// Exec implements [driver.Stmt]
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// Query implements [driver.Stmt]
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// ExecContext implements [driver.StmtExecContext]
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var (
+		res driver.Result
+		err error
+	)
+
+	if se, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = se.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(ctx, args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+
+	s.opts.log(ctx, "sql exec", s.query, args, start, err, rowsAffected(res, err)...)
+
+	return res, err
+}
+
+// QueryContext implements [driver.StmtQueryContext]
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var (
+		rows driver.Rows
+		err  error
+	)
+
+	if sq, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = sq.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(ctx, args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+
+	s.opts.log(ctx, "sql query", s.query, args, start, err)
+
+	return rows, err
+}
+
+// CheckNamedValue implements [driver.NamedValueChecker] with the sequence database/sql
+// applies to the wrapped statement: its checker or the connection one, then its
+// [driver.ColumnConverter], then the default conversion
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	nc, ok := s.Stmt.(driver.NamedValueChecker)
+	if !ok {
+		nc, ok = s.conn.(driver.NamedValueChecker)
+	}
+
+	if ok {
+		if err := nc.CheckNamedValue(nv); !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	cc, ok := s.Stmt.(driver.ColumnConverter)
+	if !ok {
+		return driver.ErrSkip
+	}
+
+	return convertColumn(cc, s.Stmt.NumInput(), nv)
+}
+
+// convertColumn converts an argument with the converter of its column like database/sql;
+// valuers are left to the default conversion
+func convertColumn(cc driver.ColumnConverter, want int, nv *driver.NamedValue) error {
+	index := nv.Ordinal - 1
+	if want <= index {
+		return nil
+	}
+
+	if _, ok := nv.Value.(driver.Valuer); ok {
+		return driver.ErrSkip
+	}
+
+	v, err := cc.ColumnConverter(index).ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+
+	if !driver.IsValue(v) {
+		return fmt.Errorf("driver ColumnConverter error converted %T to unsupported type %T", nv.Value, v)
+	}
+
+	nv.Value = v
+
+	return nil
+}
+
+// tx wraps a transaction and keeps the context it was started with
+type tx struct {
+	driver.Tx
+	ctx  context.Context
+	opts *Options
+}
+
+// Commit implements [driver.Tx]
+func (t *tx) Commit() error {
+	start := time.Now()
+	err := t.Tx.Commit()
+
+	t.opts.log(t.ctx, "sql commit", "", nil, start, err)
+
+	return err
+}
+
+// Rollback implements [driver.Tx]
+func (t *tx) Rollback() error {
+	start := time.Now()
+	err := t.Tx.Rollback()
+
+	t.opts.log(t.ctx, "sql rollback", "", nil, start, err)
+
+	return err
+}
+
+// log writes a statement record; driver.ErrSkip is a signal, not a failure, and is not logged
+func (o *Options) log(ctx context.Context, msg, query string, args []driver.NamedValue, start time.Time, err error, extra ...slog.Attr) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	duration := time.Since(start)
+
+	level := o.Level
+	if o.SlowThreshold > 0 && duration >= o.SlowThreshold {
+		level = logging.LevelWarn
+		extra = append(extra, slog.Bool("slow", true))
+	}
+
+	if err != nil {
+		level = logging.LevelError
+	}
+
+	logger := logging.LoggerFromContext(ctx)
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 5+len(extra))
+
+	if query != "" {
+		attrs = append(attrs, slog.String("query", query))
+	}
+
+	if args != nil {
+		attrs = append(attrs, slog.Int("args", len(args)))
+
+		if o.ArgValues {
+			attrs = append(attrs, argValues(args))
+		}
+	}
+
+	attrs = append(attrs, slog.Duration("duration", duration))
+	attrs = append(attrs, extra...)
+
+	if err != nil {
+		attrs = append(attrs, logging.Err(err))
+	}
+
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// rowsAffected returns the rows affected attribute of a successful execution
+func rowsAffected(res driver.Result, err error) []slog.Attr {
+	if err != nil || res == nil {
+		return nil
+	}
+
+	n, rerr := res.RowsAffected()
+	if rerr != nil {
+		return nil
+	}
+
+	return []slog.Attr{slog.Int64("rows_affected", n)}
+}
+
+// argValues logs argument values by ordinal or name
+func argValues(args []driver.NamedValue) slog.Attr {
+	attrs := make([]any, 0, len(args))
+
+	for _, a := range args {
+		key := a.Name
+		if key == "" {
+			key = fmt.Sprintf("$%d", a.Ordinal)
+		}
+
+		attrs = append(attrs, slog.Any(key, a.Value))
+	}
+
+	return slog.Group("arg_values", attrs...)
+}
+
+// namedValues converts positional values into named values
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+
+	return nv
+}
+
+// driverValues converts named values into positional values for legacy driver methods,
+// which support neither names nor contexts
+func driverValues(ctx context.Context, args []driver.NamedValue) ([]driver.Value, error) {
+	v := make([]driver.Value, len(args))
+
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+
+		v[i] = a.Value
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// WithLevel sets the level of successful statements
+// level: Level, Debug by default
+// Returns: Configuration option function
+func WithLevel(level logging.Level) Option {
+	return func(o *Options) {
+		o.Level = level
+	}
+}
+
+// WithSlowThreshold escalates statements slower than the threshold to Warn
+// threshold: Duration, 0 disables escalation
+// Returns: Configuration option function
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.SlowThreshold = threshold
+	}
+}
+
+// WithArgValues enables/disables logging argument values.
+// Values are redacted (only their count is logged) by default.
+// enabled: Whether to log values
+// Returns: Configuration option function
+func WithArgValues(enabled bool) Option {
+	return func(o *Options) {
+		o.ArgValues = enabled
+	}
+}
@@ -0,0 +1,423 @@
+package sqllog
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergei-galichev/logging"
+)
+
+// fakeDriver is an in-process driver whose statements succeed unless they contain "fail"
+// and take the configured delay
+type fakeDriver struct {
+	delay time.Duration
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{delay: d.delay}, nil }
+
+type fakeConn struct {
+	delay time.Duration
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query, delay: c.delay}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(c.delay)
+
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("syntax error")
+	}
+
+	return driver.RowsAffected(3), nil
+}
+
+type fakeStmt struct {
+	query string
+	delay time.Duration
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	time.Sleep(s.delay)
+
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = int64(1)
+
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+// fakeConnector adapts fakeDriver to driver.Connector
+type fakeConnector struct {
+	d *fakeDriver
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+// newTestContext returns a context with a JSON logger writing into buf
+func newTestContext(buf *bytes.Buffer) context.Context {
+	logger := &logging.Logger{Logger: slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: logging.LevelDebug}))}
+
+	return logging.ContextWithLogger(context.Background(), logger)
+}
+
+// decodeRecords parses JSON lines written by the test logger
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+func TestExec(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		opts      []Option
+		delay     time.Duration
+		wantLevel string
+		wantAttrs map[string]any
+	}{
+		{
+			"success",
+			"UPDATE users SET name = ? WHERE id = ?",
+			nil,
+			0,
+			"DEBUG",
+			map[string]any{"msg": "sql exec", "args": float64(2), "rows_affected": float64(3)},
+		},
+		{
+			"error",
+			"fail",
+			nil,
+			0,
+			"ERROR",
+			map[string]any{"error": "syntax error"},
+		},
+		{
+			"slow",
+			"UPDATE users SET name = ? WHERE id = ?",
+			[]Option{WithSlowThreshold(time.Millisecond)},
+			5 * time.Millisecond,
+			"WARN",
+			map[string]any{"slow": true},
+		},
+		{
+			"arg values",
+			"UPDATE users SET name = ? WHERE id = ?",
+			[]Option{WithArgValues(true), WithLevel(logging.LevelInfo)},
+			0,
+			"INFO",
+			map[string]any{"arg_values": map[string]any{"$1": "alice", "$2": float64(7)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			db := sql.OpenDB(WrapConnector(fakeConnector{&fakeDriver{delay: tt.delay}}, tt.opts...))
+			defer db.Close()
+
+			_, _ = db.ExecContext(newTestContext(&buf), tt.query, "alice", 7)
+
+			records := decodeRecords(t, &buf)
+			if len(records) != 1 {
+				t.Fatalf("records = %d, want 1: %s", len(records), buf.String())
+			}
+
+			rec := records[0]
+			if rec["level"] != tt.wantLevel || rec["query"] != tt.query {
+				t.Errorf("record = %v, want level %s and query", rec, tt.wantLevel)
+			}
+
+			for k, v := range tt.wantAttrs {
+				got, _ := json.Marshal(rec[k])
+				want, _ := json.Marshal(v)
+
+				if string(got) != string(want) {
+					t.Errorf("record[%s] = %s, want %s", k, got, want)
+				}
+			}
+
+			if _, ok := rec["arg_values"]; ok && tt.name != "arg values" {
+				t.Errorf("record = %v, want argument values redacted", rec)
+			}
+		})
+	}
+}
+
+func TestQueryAndTransaction(t *testing.T) {
+	var buf bytes.Buffer
+
+	sql.Register("sqllog-fake", Wrap(&fakeDriver{}))
+
+	db, err := sql.Open("sqllog-fake", "")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := newTestContext(&buf)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+
+	var id int
+	if err := tx.QueryRowContext(ctx, "SELECT id FROM users WHERE name = ?", "alice").Scan(&id); err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	var msgs []string
+	for _, rec := range decodeRecords(t, &buf) {
+		msgs = append(msgs, rec["msg"].(string))
+	}
+
+	want := "sql begin,sql prepare,sql query,sql commit"
+	if got := strings.Join(msgs, ","); got != want {
+		t.Errorf("records = %s, want %s", got, want)
+	}
+}
+
+// legacyConn implements only the optional interfaces that predate contexts
+type legacyConn struct {
+	args []driver.Value // arguments of the last statement
+}
+
+func (c *legacyConn) Prepare(string) (driver.Stmt, error) { return &legacyStmt{conn: c}, nil }
+func (c *legacyConn) Close() error                        { return nil }
+func (c *legacyConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (c *legacyConn) Exec(_ string, args []driver.Value) (driver.Result, error) {
+	c.args = args
+
+	return driver.RowsAffected(2), nil
+}
+
+func (c *legacyConn) Query(_ string, args []driver.Value) (driver.Rows, error) {
+	c.args = args
+
+	return &fakeRows{}, nil
+}
+
+// legacyStmt converts its string arguments to upper case through driver.ColumnConverter
+type legacyStmt struct {
+	conn *legacyConn
+}
+
+func (s *legacyStmt) Close() error  { return nil }
+func (s *legacyStmt) NumInput() int { return 1 }
+
+func (s *legacyStmt) Exec(args []driver.Value) (driver.Result, error) { return s.conn.Exec("", args) }
+func (s *legacyStmt) Query(args []driver.Value) (driver.Rows, error)  { return s.conn.Query("", args) }
+
+func (s *legacyStmt) ColumnConverter(int) driver.ValueConverter { return upperConverter{} }
+
+type upperConverter struct{}
+
+func (upperConverter) ConvertValue(v any) (driver.Value, error) {
+	if s, ok := v.(string); ok {
+		return strings.ToUpper(s), nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+type legacyConnector struct {
+	conn *legacyConn
+}
+
+func (c legacyConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c legacyConnector) Driver() driver.Driver                        { return &fakeDriver{} }
+
+func TestLegacyDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      func(ctx context.Context, db *sql.DB) error
+		wantMsgs string
+		wantArgs []driver.Value
+		wantErr  string
+	}{
+		{
+			"execer",
+			func(ctx context.Context, db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "UPDATE users SET name = ?", "alice")
+
+				return err
+			},
+			"sql exec",
+			[]driver.Value{"alice"},
+			"",
+		},
+		{
+			"queryer",
+			func(ctx context.Context, db *sql.DB) error {
+				rows, err := db.QueryContext(ctx, "SELECT id FROM users WHERE name = ?", "alice")
+				if err == nil {
+					err = rows.Close()
+				}
+
+				return err
+			},
+			"sql query",
+			[]driver.Value{"alice"},
+			"",
+		},
+		{
+			"column converter",
+			func(ctx context.Context, db *sql.DB) error {
+				s, err := db.PrepareContext(ctx, "UPDATE users SET name = ?")
+				if err != nil {
+					return err
+				}
+				defer s.Close()
+
+				_, err = s.ExecContext(ctx, "alice")
+
+				return err
+			},
+			"sql prepare,sql exec",
+			[]driver.Value{"ALICE"},
+			"",
+		},
+		{
+			"named parameters",
+			func(ctx context.Context, db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "UPDATE users SET name = :name", sql.Named("name", "alice"))
+
+				return err
+			},
+			"sql exec",
+			nil,
+			"does not support the use of Named Parameters",
+		},
+		{
+			"default transaction",
+			func(ctx context.Context, db *sql.DB) error {
+				tx, err := db.BeginTx(ctx, nil)
+				if err == nil {
+					err = tx.Rollback()
+				}
+
+				return err
+			},
+			"sql begin,sql rollback",
+			nil,
+			"",
+		},
+		{
+			"isolation level",
+			func(ctx context.Context, db *sql.DB) error {
+				_, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+
+				return err
+			},
+			"sql begin",
+			nil,
+			"non-default isolation level",
+		},
+		{
+			"read-only transaction",
+			func(ctx context.Context, db *sql.DB) error {
+				_, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+
+				return err
+			},
+			"sql begin",
+			nil,
+			"read-only transactions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			conn := &legacyConn{}
+
+			db := sql.OpenDB(WrapConnector(legacyConnector{conn}))
+			defer db.Close()
+
+			err := tt.run(newTestContext(&buf), db)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+
+			var msgs []string
+			for _, rec := range decodeRecords(t, &buf) {
+				msgs = append(msgs, rec["msg"].(string))
+			}
+
+			if got := strings.Join(msgs, ","); got != tt.wantMsgs {
+				t.Errorf("records = %s, want %s", got, tt.wantMsgs)
+			}
+
+			if tt.wantArgs != nil && !slices.Equal(conn.args, tt.wantArgs) {
+				t.Errorf("driver args = %v, want %v", conn.args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sergei-galichev/logging/internal/caller"
+)
+
+// stdLevelPrefixes maps "[LEVEL]" message prefixes to levels
+var stdLevelPrefixes = map[string]Level{
+	"DEBUG":   LevelDebug,
+	"INFO":    LevelInfo,
+	"WARN":    LevelWarn,
+	"WARNING": LevelWarn,
+	"ERR":     LevelError,
+	"ERROR":   LevelError,
+	"FATAL":   LevelFatal,
+}
+
+// stdWriter is an io.Writer turning lines of the standard log package into records
+type stdWriter struct {
+	logger *slog.Logger
+	level  Level
+}
+
+// Write implements [io.Writer]
+func (w *stdWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	level, msg := parseStdLevel(msg, w.level)
+
+	ctx := context.Background()
+	if !w.logger.Enabled(ctx, level) {
+		return len(p), nil
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, caller.PC(1, "log."))
+	_ = w.logger.Handler().Handle(ctx, r)
+
+	return len(p), nil
+}
+
+// parseStdLevel extracts an optional "[LEVEL]" prefix from the message
+func parseStdLevel(msg string, fallback Level) (Level, string) {
+	if !strings.HasPrefix(msg, "[") {
+		return fallback, msg
+	}
+
+	end := strings.IndexByte(msg, ']')
+	if end < 0 {
+		return fallback, msg
+	}
+
+	level, ok := stdLevelPrefixes[strings.ToUpper(msg[1:end])]
+	if !ok {
+		return fallback, msg
+	}
+
+	return level, strings.TrimLeft(msg[end+1:], " ")
+}
+
+// StdLogger returns a standard library *log.Logger writing into this logger,
+// useful for APIs such as http.Server.ErrorLog.
+// Messages starting with a "[LEVEL]" prefix (DEBUG, INFO, WARN, ERROR, FATAL)
+// are logged at that level, others at the given level.
+// level: Default level of messages without a prefix
+// Returns: Standard library logger
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(&stdWriter{logger: l.Logger, level: level}, "", 0)
+}
+
+// RedirectStdLog routes the output of the standard log package into the logger.
+// The source of records points to the caller of log.Printf and friends, and
+// "[LEVEL]" prefixes are parsed as in [Logger.StdLogger].
+// logger: Logger receiving the messages
+// level: Default level of messages without a prefix
+// Returns: Function restoring the previous output, flags and prefix of the log package
+func RedirectStdLog(logger *Logger, level Level) func() {
+	prevOutput := log.Writer()
+	prevFlags := log.Flags()
+	prevPrefix := log.Prefix()
+
+	log.SetOutput(&stdWriter{logger: logger.Logger, level: level})
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       string
+		wantLevel string
+		wantMsg   string
+	}{
+		{"default level", "plain message", "level=WARN", `msg="plain message"`},
+		{"error prefix", "[ERROR] broken pipe", "level=ERROR", `msg="broken pipe"`},
+		{"lower-case prefix", "[debug] details", "level=DEBUG", "msg=details"},
+		{"warning prefix", "[WARNING] disk almost full", "level=WARN", `msg="disk almost full"`},
+		{"unknown prefix", "[conn 1] closed", "level=WARN", `msg="[conn 1] closed"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			logger := &Logger{slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level:     LevelDebug,
+				AddSource: true,
+			}))}
+
+			logger.StdLogger(LevelWarn).Print(tt.msg)
+
+			out := buf.String()
+			if !strings.Contains(out, tt.wantLevel) || !strings.Contains(out, tt.wantMsg) {
+				t.Errorf("output = %q, want %s and %s", out, tt.wantLevel, tt.wantMsg)
+			}
+
+			if !strings.Contains(out, "stdlog_test.go") {
+				t.Errorf("output = %q, want source pointing to stdlog_test.go", out)
+			}
+		})
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := &Logger{slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{AddSource: true}))}
+
+	restore := RedirectStdLog(logger, LevelInfo)
+	log.Printf("[ERROR] failed after %d attempts", 3)
+	log.Println("redirected")
+	restore()
+
+	out := buf.String()
+
+	for _, want := range []string{`level=ERROR`, `msg="failed after 3 attempts"`, `msg=redirected`, "stdlog_test.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want it to contain %s", out, want)
+		}
+	}
+
+	if _, ok := log.Writer().(*stdWriter); ok {
+		t.Errorf("restore() did not restore the log package output")
+	}
+}
@@ -0,0 +1,274 @@
+// Package zaplog lets zap call sites log through a logging.Logger, so that code
+// migrating from go.uber.org/zap shares the output format and sinks of NewLogger.
+//
+//	zl := zaplog.New(logger)
+//	zl.Info("user created", zap.String("user", name), zap.Int("age", age))
+//
+// Fields are converted to attributes, zap namespaces to groups and zap logger
+// names to [logging.Logger.Named] names. DPanic and Panic map to Error, Fatal to
+// [logging.LevelFatal]; zap still panics or exits after writing the record.
+package zaplog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/sergei-galichev/logging"
+	"github.com/sergei-galichev/logging/internal/caller"
+)
+
+const (
+	// stacktraceKey is the attribute key of stack traces captured by zap.AddStacktrace
+	stacktraceKey = "stacktrace"
+
+	// zapPrefix matches the functions of zap and zapcore skipped when looking for the caller
+	zapPrefix = "go.uber.org/zap"
+)
+
+// Core is a [zapcore.Core] writing into a [logging.Logger]
+type Core struct {
+	base   *logging.Logger // logger passed to NewCore
+	logger *slog.Logger    // base with fields applied
+	fields []zapcore.Field // fields applied, replayed on named loggers
+	named  *sync.Map       // zap logger name -> *slog.Logger with fields applied
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// NewCore creates a zap core writing into the logger
+// logger: Logger receiving the records
+// Returns: Core for zap.New
+func NewCore(logger *logging.Logger) *Core {
+	return &Core{
+		base:   logger,
+		logger: logger.Logger,
+		named:  &sync.Map{},
+	}
+}
+
+// New returns a zap logger writing into the logger
+// logger: Logger receiving the records
+// opts: zap options such as zap.AddStacktrace
+// Returns: zap logger
+func New(logger *logging.Logger, opts ...zap.Option) *zap.Logger {
+	return zap.New(NewCore(logger), opts...)
+}
+
+// Enabled implements [zapcore.LevelEnabler]
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.logger.Enabled(context.Background(), toLevel(level))
+}
+
+// With implements [zapcore.Core]
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{
+		base:   c.base,
+		logger: withFields(c.logger, fields),
+		fields: append(slices.Clip(c.fields), fields...),
+		named:  &sync.Map{},
+	}
+}
+
+// Check implements [zapcore.Core]
+func (c *Core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write implements [zapcore.Core]
+func (c *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	r := slog.NewRecord(entry.Time, toLevel(entry.Level), entry.Message, caller.PC(1, zapPrefix))
+	r.AddAttrs(toAttrs(fields)...)
+
+	if entry.Stack != "" {
+		r.AddAttrs(slog.String(stacktraceKey, entry.Stack))
+	}
+
+	return c.loggerFor(entry.LoggerName).Handler().Handle(context.Background(), r)
+}
+
+// Sync implements [zapcore.Core]; records are written by the handler synchronously
+func (c *Core) Sync() error {
+	return nil
+}
+
+// loggerFor returns the logger named after the zap logger name
+func (c *Core) loggerFor(name string) *slog.Logger {
+	if name == "" {
+		return c.logger
+	}
+
+	if l, ok := c.named.Load(name); ok {
+		return l.(*slog.Logger)
+	}
+
+	l := withFields(c.base.Named(name).Logger, c.fields)
+	c.named.Store(name, l)
+
+	return l
+}
+
+// toLevel maps zap levels to logging levels
+func toLevel(level zapcore.Level) logging.Level {
+	switch level {
+	case zapcore.DebugLevel:
+		return logging.LevelDebug
+	case zapcore.InfoLevel:
+		return logging.LevelInfo
+	case zapcore.WarnLevel:
+		return logging.LevelWarn
+	case zapcore.FatalLevel:
+		return logging.LevelFatal
+	default:
+		return logging.LevelError
+	}
+}
+
+// withFields applies fields to the logger, opening a group for every namespace
+func withFields(l *slog.Logger, fields []zapcore.Field) *slog.Logger {
+	var args []any
+
+	for _, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			l = l.With(args...).WithGroup(f.Key)
+			args = nil
+
+			continue
+		}
+
+		if a := toAttr(f); !a.Equal(slog.Attr{}) {
+			args = append(args, a)
+		}
+	}
+
+	return l.With(args...)
+}
+
+// toAttrs converts fields to attributes, nesting the fields following a namespace
+func toAttrs(fields []zapcore.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			return append(attrs, slog.Attr{Key: f.Key, Value: slog.GroupValue(toAttrs(fields[i+1:])...)})
+		}
+
+		if a := toAttr(f); !a.Equal(slog.Attr{}) {
+			attrs = append(attrs, a)
+		}
+	}
+
+	return attrs
+}
+
+// toAttr converts a field to an attribute, returning an empty attribute for skipped fields.
+// As in zapcore, a panicking Error or String method is logged as "<key>Error" instead of
+// the field, and a nil pointer implementing them as "<nil>".
+func toAttr(f zapcore.Field) (a slog.Attr) {
+	defer func() {
+		if r := recover(); r != nil {
+			a = panicAttr(f, r)
+		}
+	}()
+
+	switch f.Type {
+	case zapcore.StringType:
+		return slog.String(f.Key, f.String)
+	case zapcore.BoolType:
+		return slog.Bool(f.Key, f.Integer == 1)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return slog.Int64(f.Key, f.Integer)
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return slog.Uint64(f.Key, uint64(f.Integer))
+	case zapcore.Float64Type:
+		return slog.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return slog.Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer))))
+	case zapcore.DurationType:
+		return slog.Duration(f.Key, time.Duration(f.Integer))
+	case zapcore.TimeType:
+		t := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok {
+			t = t.In(loc)
+		}
+
+		return slog.Time(f.Key, t)
+	case zapcore.TimeFullType:
+		return slog.Any(f.Key, f.Interface)
+	case zapcore.ByteStringType:
+		b, _ := f.Interface.([]byte)
+
+		return slog.String(f.Key, string(b))
+	case zapcore.BinaryType:
+		b, _ := f.Interface.([]byte)
+
+		return logging.Base64(f.Key, b)
+	case zapcore.ErrorType:
+		err, ok := f.Interface.(error)
+		if !ok {
+			return slog.Any(f.Key, f.Interface)
+		}
+
+		return slog.String(f.Key, err.Error())
+	case zapcore.StringerType:
+		s, ok := f.Interface.(fmt.Stringer)
+		if !ok {
+			return slog.String(f.Key, "<nil>")
+		}
+
+		return slog.String(f.Key, s.String())
+	case zapcore.ReflectType, zapcore.Complex128Type, zapcore.Complex64Type:
+		return slog.Any(f.Key, f.Interface)
+	case zapcore.SkipType:
+		return slog.Attr{}
+	default:
+		// object and array marshalers are encoded into maps and slices
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+
+		return mapAttr(f.Key, enc.Fields[f.Key])
+	}
+}
+
+// panicAttr reports a panic raised while converting a field, the way zapcore does
+func panicAttr(f zapcore.Field, r any) slog.Attr {
+	if v := reflect.ValueOf(f.Interface); v.Kind() == reflect.Pointer && v.IsNil() {
+		return slog.String(f.Key, "<nil>")
+	}
+
+	return slog.String(f.Key+"Error", fmt.Sprintf("PANIC=%v", r))
+}
+
+// mapAttr converts values produced by zapcore.MapObjectEncoder, turning objects into groups
+func mapAttr(key string, v any) slog.Attr {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return slog.Any(key, v)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, mapAttr(k, m[k]))
+	}
+
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
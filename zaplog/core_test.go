@@ -0,0 +1,160 @@
+package zaplog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/sergei-galichev/logging"
+)
+
+type point struct{ x, y int }
+
+// nilError implements error on a pointer receiver, panicking for a typed nil
+type nilError struct{ msg string }
+
+func (e *nilError) Error() string { return e.msg }
+
+// panicStringer panics in String
+type panicStringer struct{}
+
+func (panicStringer) String() string { panic("boom") }
+
+func (p point) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("y", p.y)
+	enc.AddInt("x", p.x)
+
+	return nil
+}
+
+func TestCore(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *zap.Logger)
+		want string
+	}{
+		{
+			"scalar fields",
+			func(l *zap.Logger) {
+				l.Info("msg", zap.String("s", "v"), zap.Int("n", -1), zap.Uint8("u", 2), zap.Bool("ok", true),
+					zap.Float32("f", 0.5), zap.Duration("d", time.Second))
+			},
+			"level=INFO msg=msg s=v n=-1 u=2 ok=true f=0.5 d=1s",
+		},
+		{"debug", func(l *zap.Logger) { l.Debug("msg") }, "level=DEBUG msg=msg"},
+		{"dpanic", func(l *zap.Logger) { l.DPanic("msg") }, "level=ERROR msg=msg"},
+		{"error field", func(l *zap.Logger) { l.Warn("msg", zap.Error(errors.New("boom"))) }, "level=WARN msg=msg error=boom"},
+		{"nil error skipped", func(l *zap.Logger) { l.Warn("msg", zap.Error(nil)) }, "level=WARN msg=msg"},
+		{"object", func(l *zap.Logger) { l.Info("msg", zap.Object("p", point{1, 2})) }, "level=INFO msg=msg p.x=1 p.y=2"},
+		{
+			"namespace",
+			func(l *zap.Logger) { l.Info("msg", zap.String("a", "1"), zap.Namespace("ns"), zap.String("b", "2")) },
+			"level=INFO msg=msg a=1 ns.b=2",
+		},
+		{
+			"with",
+			func(l *zap.Logger) {
+				l.With(zap.String("a", "1"), zap.Namespace("ns")).Info("msg", zap.String("b", "2"))
+			},
+			"level=INFO msg=msg a=1 ns.b=2",
+		},
+		{
+			"named",
+			func(l *zap.Logger) { l.With(zap.String("a", "1")).Named("api").Named("users").Info("msg") },
+			"level=INFO msg=msg logger=api.users a=1",
+		},
+		{
+			"nil stringer",
+			func(l *zap.Logger) { l.Info("msg", zap.Stringer("s", nil), zap.Stringer("p", (*panicStringer)(nil))) },
+			"level=INFO msg=msg s=<nil> p=<nil>",
+		},
+		{
+			"panicking stringer",
+			func(l *zap.Logger) { l.Info("msg", zap.Stringer("s", panicStringer{})) },
+			`level=INFO msg=msg sError="PANIC=boom"`,
+		},
+		{
+			"typed nil error",
+			func(l *zap.Logger) { l.Warn("msg", zap.Error((*nilError)(nil))) },
+			"level=WARN msg=msg error=<nil>",
+		},
+		{
+			"byte fields",
+			func(l *zap.Logger) {
+				l.Info("msg", zap.ByteString("bs", []byte("text")), zap.Binary("bin", []byte{1, 2}),
+					zapcore.Field{Key: "bad", Type: zapcore.ByteStringType, Interface: "not bytes"})
+			},
+			`level=INFO msg=msg bs=text bin="AQI=" bad=""`,
+		},
+		{
+			"sugared",
+			func(l *zap.Logger) { l.Sugar().Infow("msg", "k", "v") },
+			"level=INFO msg=msg k=v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(New(newTestLogger(&buf, logging.LevelDebug, false)))
+
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoreEnabled(t *testing.T) {
+	l := New(newTestLogger(&bytes.Buffer{}, logging.LevelWarn, false))
+
+	tests := []struct {
+		level zapcore.Level
+		want  bool
+	}{
+		{zapcore.DebugLevel, false},
+		{zapcore.InfoLevel, false},
+		{zapcore.WarnLevel, true},
+		{zapcore.ErrorLevel, true},
+	}
+
+	for _, tt := range tests {
+		if got := l.Core().Enabled(tt.level); got != tt.want {
+			t.Errorf("Enabled(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestCoreSource(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(newTestLogger(&buf, logging.LevelDebug, true))
+	l.Info("msg")
+	l.Sugar().Infof("msg %d", 1)
+
+	if got := strings.Count(buf.String(), "core_test.go"); got != 2 {
+		t.Errorf("output = %q, want source pointing to core_test.go twice", buf.String())
+	}
+}
+
+// newTestLogger returns a text logger without time for deterministic output
+func newTestLogger(buf *bytes.Buffer, level logging.Level, source bool) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:     level,
+		AddSource: source,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))}
+}
@@ -0,0 +1,229 @@
+// Package zerologlog lets zerolog call sites log through a logging.Logger, so that code
+// migrating from github.com/rs/zerolog shares the output format and sinks of NewLogger.
+//
+//	zl := zerologlog.New(logger)
+//	zl.Info().Str("user", name).Int("age", age).Msg("user created")
+//
+// The JSON events written by zerolog are decoded back into attributes in field order,
+// nested objects becoming groups. The level, message and timestamp fields are replaced
+// by the record ones. Trace maps to Debug-4, Panic and Fatal to [logging.LevelFatal]
+// and events without a level to Info.
+package zerologlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sergei-galichev/logging"
+	"github.com/sergei-galichev/logging/internal/caller"
+)
+
+// zerologPrefix matches the functions of zerolog skipped when looking for the caller
+const zerologPrefix = "github.com/rs/zerolog."
+
+// levels lists zerolog levels from the most to the least verbose
+var levels = []zerolog.Level{
+	zerolog.TraceLevel,
+	zerolog.DebugLevel,
+	zerolog.InfoLevel,
+	zerolog.WarnLevel,
+	zerolog.ErrorLevel,
+	zerolog.FatalLevel,
+	zerolog.PanicLevel,
+}
+
+// Writer is a [zerolog.LevelWriter] writing events into a [logging.Logger]
+type Writer struct {
+	logger *logging.Logger
+}
+
+var _ zerolog.LevelWriter = (*Writer)(nil)
+
+// NewWriter creates a writer for zerolog.New writing into the logger
+// logger: Logger receiving the events
+// Returns: Writer
+func NewWriter(logger *logging.Logger) *Writer {
+	return &Writer{logger: logger}
+}
+
+// New returns a zerolog logger writing into the logger.
+// The zerolog level is set to the lowest level enabled on the logger at creation time.
+// logger: Logger receiving the events
+// Returns: zerolog logger
+func New(logger *logging.Logger) zerolog.Logger {
+	return zerolog.New(NewWriter(logger)).Level(minLevel(logger))
+}
+
+// Write implements [io.Writer] for events without a level
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter]
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	ctx := context.Background()
+
+	lvl := toLevel(level)
+	if !w.logger.Enabled(ctx, lvl) {
+		return len(p), nil
+	}
+
+	msg, attrs, err := decodeEvent(p)
+	if err != nil {
+		return 0, fmt.Errorf("decode zerolog event: %w", err)
+	}
+
+	r := slog.NewRecord(time.Now(), lvl, msg, caller.PC(1, zerologPrefix))
+	r.AddAttrs(attrs...)
+
+	return len(p), w.logger.Handler().Handle(ctx, r)
+}
+
+// toLevel maps zerolog levels to logging levels
+func toLevel(level zerolog.Level) logging.Level {
+	switch level {
+	case zerolog.TraceLevel:
+		return logging.LevelDebug - 4
+	case zerolog.DebugLevel:
+		return logging.LevelDebug
+	case zerolog.WarnLevel:
+		return logging.LevelWarn
+	case zerolog.ErrorLevel:
+		return logging.LevelError
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return logging.LevelFatal
+	default:
+		return logging.LevelInfo
+	}
+}
+
+// minLevel returns the most verbose zerolog level enabled on the logger
+func minLevel(logger *logging.Logger) zerolog.Level {
+	for _, level := range levels {
+		if logger.Enabled(context.Background(), toLevel(level)) {
+			return level
+		}
+	}
+
+	return zerolog.Disabled
+}
+
+// decodeEvent decodes a JSON event into its message and the other fields in order
+func decodeEvent(p []byte) (string, []slog.Attr, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", nil, err
+	}
+
+	fields, err := decodeFields(dec)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var msg string
+
+	attrs := fields[:0]
+
+	for _, a := range fields {
+		switch a.Key {
+		case zerolog.MessageFieldName:
+			msg = a.Value.String()
+		case zerolog.LevelFieldName, zerolog.TimestampFieldName:
+		default:
+			attrs = append(attrs, a)
+		}
+	}
+
+	return msg, attrs, nil
+}
+
+// decodeFields decodes the fields of an object whose opening brace was read
+func decodeFields(dec *json.Decoder) ([]slog.Attr, error) {
+	var attrs []slog.Attr
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := tok.(string)
+
+		a, err := decodeValue(dec, key)
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, a)
+	}
+
+	// closing brace
+	_, err := dec.Token()
+
+	return attrs, err
+}
+
+// decodeValue decodes the next value, turning objects into groups
+func decodeValue(dec *json.Decoder, key string) (slog.Attr, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return slog.Attr{}, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			attrs, err := decodeFields(dec)
+
+			return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}, err
+		}
+
+		var values []any
+
+		for dec.More() {
+			var value any
+			if err := dec.Decode(&value); err != nil {
+				return slog.Attr{}, err
+			}
+
+			values = append(values, value)
+		}
+
+		// closing bracket
+		_, err := dec.Token()
+
+		return slog.Any(key, values), err
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return slog.Int64(key, i), nil
+		}
+
+		f, err := v.Float64()
+
+		return slog.Float64(key, f), err
+	default:
+		return slog.Any(key, v), nil
+	}
+}
+
+// expectDelim reads the next token and checks that it is the delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package zerologlog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sergei-galichev/logging"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l zerolog.Logger)
+		want string
+	}{
+		{
+			"fields in order",
+			func(l zerolog.Logger) {
+				l.Info().Str("s", "v").Int("n", -1).Bool("ok", true).Float64("f", 0.5).Msg("msg")
+			},
+			"level=INFO msg=msg s=v n=-1 ok=true f=0.5",
+		},
+		{"trace", func(l zerolog.Logger) { l.Trace().Msg("msg") }, "level=DEBUG-4 msg=msg"},
+		{"no level", func(l zerolog.Logger) { l.Log().Msg("msg") }, "level=INFO msg=msg"},
+		{"error", func(l zerolog.Logger) { l.Error().Err(errors.New("boom")).Msg("failed") }, "level=ERROR msg=failed error=boom"},
+		{
+			"with",
+			func(l zerolog.Logger) {
+				w := l.With().Str("svc", "api").Logger()
+				w.Warn().Msg("msg")
+			},
+			"level=WARN msg=msg svc=api",
+		},
+		{
+			"nested",
+			func(l zerolog.Logger) {
+				l.Info().Dict("p", zerolog.Dict().Int("y", 2).Int("x", 1)).Ints("a", []int{1, 2}).Send()
+			},
+			`level=INFO msg="" p.y=2 p.x=1 a="[1 2]"`,
+		},
+		{
+			"timestamp dropped",
+			func(l zerolog.Logger) {
+				w := l.With().Timestamp().Logger()
+				w.Info().Dur("d", time.Second).Msg("msg")
+			},
+			"level=INFO msg=msg d=1000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tt.log(New(newTestLogger(&buf, logging.LevelDebug-4, false)))
+
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLevel(t *testing.T) {
+	tests := []struct {
+		level logging.Level
+		want  zerolog.Level
+	}{
+		{logging.LevelDebug - 4, zerolog.TraceLevel},
+		{logging.LevelDebug, zerolog.DebugLevel},
+		{logging.LevelInfo, zerolog.InfoLevel},
+		{logging.LevelWarn, zerolog.WarnLevel},
+		{logging.LevelError, zerolog.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		if got := New(newTestLogger(&bytes.Buffer{}, tt.level, false)).GetLevel(); got != tt.want {
+			t.Errorf("New() level for %v = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestWriterSource(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := New(newTestLogger(&buf, logging.LevelDebug, true))
+	l.Info().Msg("msg")
+	l.Info().Msgf("msg %d", 1)
+
+	if got := strings.Count(buf.String(), "writer_test.go"); got != 2 {
+		t.Errorf("output = %q, want source pointing to writer_test.go twice", buf.String())
+	}
+}
+
+func TestWriterInvalidEvent(t *testing.T) {
+	if _, err := NewWriter(newTestLogger(&bytes.Buffer{}, logging.LevelInfo, false)).Write([]byte("not json")); err == nil {
+		t.Error("Write() error = nil, want decode error")
+	}
+}
+
+// newTestLogger returns a text logger without time for deterministic output
+func newTestLogger(buf *bytes.Buffer, level logging.Level, source bool) *logging.Logger {
+	return &logging.Logger{Logger: slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level:     level,
+		AddSource: source,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))}
+}